@@ -0,0 +1,530 @@
+package logstore
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// ArchiveCompression defines how archives are compressed
+type ArchiveCompression int
+
+const (
+	// ArchiveCompressionGzip compresses archives with gzip. This is the default.
+	ArchiveCompressionGzip ArchiveCompression = iota
+
+	// ArchiveCompressionZstd compresses archives with zstd
+	ArchiveCompressionZstd
+)
+
+// String returns the name of the compression, as used in manifests
+func (c ArchiveCompression) String() string {
+	if c == ArchiveCompressionZstd {
+		return "zstd"
+	}
+	return "gzip"
+}
+
+// extension returns the file extension of an archive
+func (c ArchiveCompression) extension() string {
+	if c == ArchiveCompressionZstd {
+		return ".ndjson.zst"
+	}
+	return ".ndjson.gz"
+}
+
+// writer wraps w with the compressor
+func (c ArchiveCompression) writer(w io.Writer) (io.WriteCloser, error) {
+	if c == ArchiveCompressionZstd {
+		return zstd.NewWriter(w)
+	}
+	return gzip.NewWriter(w), nil
+}
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// archiveReader decompresses an archive, detecting the compression from
+// its first bytes
+func archiveReader(r io.Reader) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(r)
+
+	magic, err := buffered.Peek(len(zstdMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return gzip.NewReader(buffered)
+	case bytes.HasPrefix(magic, zstdMagic):
+		decoder, err := zstd.NewReader(buffered)
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	}
+
+	return nil, errors.New("archive: unknown compression")
+}
+
+// ArchiveManifest describes the content of an archive
+type ArchiveManifest struct {
+	// File is the name of the archive file, when written by an Archiver
+	File string `json:"file,omitempty"`
+
+	// Compression is the compression of the archive, "gzip" or "zstd"
+	Compression string `json:"compression"`
+
+	// From is the time of the oldest entry in the archive
+	From time.Time `json:"from,omitzero"`
+
+	// To is the time of the newest entry in the archive
+	To time.Time `json:"to,omitzero"`
+
+	// Count is the number of entries in the archive
+	Count int64 `json:"count"`
+
+	// Checksum is the hex encoded SHA-256 of the compressed archive
+	Checksum string `json:"checksum"`
+
+	// CreatedAt is the time the archive was written
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ArchiveOptions define the options for writing an archive
+type ArchiveOptions struct {
+	// Compression of the archive, by default gzip
+	Compression ArchiveCompression
+
+	// BatchSize is the number of rows read per query, by default 500
+	BatchSize int
+}
+
+// Archive streams the logs matching the query filters to w as compressed
+// NDJSON, one LogRecord per line, oldest first, and returns the manifest
+// of what was written. The logs are not deleted, see Archiver for that.
+// The limit, offset and order of the query are ignored.
+func (st *storeImplementation) Archive(ctx context.Context, query LogQueryInterface, w io.Writer, opts ...ArchiveOptions) (ArchiveManifest, error) {
+	if query == nil {
+		query = LogQuery()
+	}
+
+	if err := query.Validate(); err != nil {
+		return ArchiveManifest{}, err
+	}
+
+	options := ArchiveOptions{}
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	manifest := ArchiveManifest{
+		Compression: options.Compression.String(),
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	hash := sha256.New()
+	compressor, err := options.Compression.writer(io.MultiWriter(w, hash))
+	if err != nil {
+		return manifest, err
+	}
+
+	encoder := json.NewEncoder(compressor)
+
+	err = st.forEachLogBatch(ctx, query, options.BatchSize, func(batch []LogInterface) error {
+		for _, logEntry := range batch {
+			if err := encoder.Encode(NewLogRecord(logEntry)); err != nil {
+				return err
+			}
+
+			if manifest.From.IsZero() || logEntry.GetTime().Before(manifest.From) {
+				manifest.From = logEntry.GetTime()
+			}
+
+			if logEntry.GetTime().After(manifest.To) {
+				manifest.To = logEntry.GetTime()
+			}
+
+			manifest.Count++
+		}
+		return nil
+	})
+
+	if err != nil {
+		compressor.Close()
+		return manifest, err
+	}
+
+	if err := compressor.Close(); err != nil {
+		return manifest, err
+	}
+
+	manifest.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	return manifest, nil
+}
+
+// ArchiverOptions define the options for creating an archiver
+type ArchiverOptions struct {
+	// Store is the log store to archive from, as returned by NewStore
+	Store StoreInterface
+
+	// Directory is where the archives and their manifests are written
+	Directory string
+
+	// Compression of the archives, by default gzip
+	Compression ArchiveCompression
+
+	// BatchSize is the number of rows read and deleted per query, by default 500
+	BatchSize int
+
+	// OlderThan is the age after which Run archives entries
+	OlderThan time.Duration
+
+	// Interval is how often Run archives entries, by default an hour
+	Interval time.Duration
+}
+
+// Archiver moves logs out of the log table into compressed archive files,
+// and restores them back
+type Archiver struct {
+	store       *storeImplementation
+	directory   string
+	compression ArchiveCompression
+	batchSize   int
+	olderThan   time.Duration
+	interval    time.Duration
+}
+
+// NewArchiver creates a new archiver
+func NewArchiver(opts ArchiverOptions) (*Archiver, error) {
+	if opts.Store == nil {
+		return nil, errors.New("archiver: Store is required")
+	}
+
+	store, ok := opts.Store.(*storeImplementation)
+	if !ok {
+		return nil, errors.New("archiver: Store must be created with NewStore")
+	}
+
+	if opts.Directory == "" {
+		return nil, errors.New("archiver: Directory is required")
+	}
+
+	if err := os.MkdirAll(opts.Directory, 0o755); err != nil {
+		return nil, err
+	}
+
+	archiver := &Archiver{
+		store:       store,
+		directory:   opts.Directory,
+		compression: opts.Compression,
+		batchSize:   opts.BatchSize,
+		olderThan:   opts.OlderThan,
+		interval:    opts.Interval,
+	}
+
+	if archiver.batchSize <= 0 {
+		archiver.batchSize = defaultBatchSize
+	}
+
+	if archiver.interval <= 0 {
+		archiver.interval = time.Hour
+	}
+
+	return archiver, nil
+}
+
+// Archive writes the logs matching the query filters to a new archive file
+// with a manifest next to it, then deletes them from the store. The logs
+// are only deleted once both files are synced to disk, reading their IDs
+// back from the archive a batch at a time, so memory does not grow with the
+// archive and logs added meanwhile are kept. Nothing is written when no
+// logs match.
+func (a *Archiver) Archive(ctx context.Context, query LogQueryInterface) (ArchiveManifest, error) {
+	temp, err := os.CreateTemp(a.directory, "archive-*.tmp")
+	if err != nil {
+		return ArchiveManifest{}, err
+	}
+
+	// a no-op once the temporary file is renamed
+	defer os.Remove(temp.Name())
+
+	manifest, err := a.store.Archive(ctx, query, temp, ArchiveOptions{
+		Compression: a.compression,
+		BatchSize:   a.batchSize,
+	})
+
+	if err != nil {
+		temp.Close()
+		return manifest, err
+	}
+
+	if manifest.Count == 0 {
+		temp.Close()
+		return manifest, nil
+	}
+
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return manifest, err
+	}
+
+	if err := temp.Close(); err != nil {
+		return manifest, err
+	}
+
+	base := a.archiveBaseName(manifest)
+	manifest.File = base + a.compression.extension()
+
+	if err := os.Rename(temp.Name(), filepath.Join(a.directory, manifest.File)); err != nil {
+		return manifest, err
+	}
+
+	if err := a.writeManifest(base, manifest); err != nil {
+		return manifest, err
+	}
+
+	if err := syncDirectory(a.directory); err != nil {
+		return manifest, err
+	}
+
+	return manifest, a.deleteArchived(ctx, filepath.Join(a.directory, manifest.File))
+}
+
+// deleteArchived deletes the logs of an archive file from the store, one
+// batch of IDs at a time
+func (a *Archiver) deleteArchived(ctx context.Context, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader, err := archiveReader(f)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	decoder := json.NewDecoder(reader)
+	ids := make([]string, 0, a.batchSize)
+
+	for {
+		var record struct {
+			ID string `json:"id"`
+		}
+
+		err := decoder.Decode(&record)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		if err == nil {
+			ids = append(ids, record.ID)
+		}
+
+		if len(ids) > 0 && (len(ids) >= a.batchSize || err != nil) {
+			if err := a.store.LogDeleteByIDs(ctx, ids); err != nil {
+				return err
+			}
+			ids = ids[:0]
+		}
+
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+// Run archives the entries older than OlderThan every Interval, until the
+// context is done
+func (a *Archiver) Run(ctx context.Context) error {
+	if a.olderThan <= 0 {
+		return errors.New("archiver: OlderThan is required to run")
+	}
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		cutoff := time.Now().UTC().Add(-a.olderThan)
+
+		manifest, err := a.Archive(ctx, LogQuery().SetTimeLte(cutoff.Format(time.DateTime)))
+		if err != nil && ctx.Err() == nil {
+			a.store.logger.Error("archiver: archiving failed", "error", err)
+		} else if manifest.Count > 0 && a.store.debugEnabled {
+			a.store.logger.Info("archiver: archived logs", "file", manifest.File, "count", manifest.Count)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Restore loads an archive file back into the store, and returns the
+// number of entries restored. When the archive has a manifest next to it
+// the checksum is verified first.
+func (a *Archiver) Restore(ctx context.Context, file string) (int64, error) {
+	manifestFile := strings.TrimSuffix(strings.TrimSuffix(file, ".ndjson.gz"), ".ndjson.zst") + ".manifest.json"
+
+	if data, err := os.ReadFile(manifestFile); err == nil {
+		manifest := ArchiveManifest{}
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return 0, fmt.Errorf("archive: reading manifest: %w", err)
+		}
+
+		checksum, err := fileChecksum(file)
+		if err != nil {
+			return 0, err
+		}
+
+		if checksum != manifest.Checksum {
+			return 0, errors.New("archive: checksum does not match the manifest")
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	reader, err := archiveReader(f)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	decoder := json.NewDecoder(reader)
+	batch := make([]LogInterface, 0, a.batchSize)
+	var count int64
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+
+		record := LogRecord{}
+		err := decoder.Decode(&record)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return count, err
+		}
+
+		if err == nil {
+			logEntry := record.ToLog()
+			a.store.applyTenant(logEntry)
+			batch = append(batch, logEntry)
+		}
+
+		if len(batch) > 0 && (len(batch) >= a.batchSize || err != nil) {
+			if err := a.store.insertLogs(ctx, batch, a.batchSize); err != nil {
+				return count, err
+			}
+
+			count += int64(len(batch))
+			batch = batch[:0]
+		}
+
+		if err != nil {
+			return count, nil
+		}
+	}
+}
+
+// archiveBaseName names an archive after the log table and its time range
+func (a *Archiver) archiveBaseName(manifest ArchiveManifest) string {
+	const layout = "20060102T150405Z"
+
+	base := fmt.Sprintf("%s_%s_%s",
+		a.store.GetLogTableName(),
+		manifest.From.UTC().Format(layout),
+		manifest.To.UTC().Format(layout))
+
+	// archives of the same range, i.e. late entries, get a sequence number
+	name := base
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(a.directory, name+a.compression.extension())); errors.Is(err, os.ErrNotExist) {
+			return name
+		}
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+}
+
+// writeManifest writes and syncs the manifest of an archive
+func (a *Archiver) writeManifest(base string, manifest ArchiveManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(a.directory, "manifest-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return err
+	}
+
+	if err := temp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(temp.Name(), filepath.Join(a.directory, base+".manifest.json"))
+}
+
+// fileChecksum returns the hex encoded SHA-256 of a file
+func fileChecksum(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// syncDirectory syncs a directory, so renames within it are durable
+func syncDirectory(directory string) error {
+	d, err := os.Open(directory)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
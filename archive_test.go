@@ -0,0 +1,229 @@
+package logstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_Archiver_ArchiveAndRestore(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_archive",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+	old := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	// entries sharing a time, paged in batches smaller than their number
+	for range 5 {
+		if err := s.LogCreate(ctx, NewLog().SetLevel(LEVEL_INFO).SetMessage("old").SetTime(old)); err != nil {
+			t.Fatalf("unexpected error from LogCreate: %v", err)
+		}
+	}
+
+	if err := s.Info("recent"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, compression := range []ArchiveCompression{ArchiveCompressionGzip, ArchiveCompressionZstd} {
+		directory := t.TempDir()
+
+		archiver, err := NewArchiver(ArchiverOptions{
+			Store:       s,
+			Directory:   directory,
+			Compression: compression,
+			BatchSize:   2,
+		})
+
+		if err != nil {
+			t.Fatalf("unexpected error from NewArchiver: %v", err)
+		}
+
+		manifest, err := archiver.Archive(ctx, LogQuery().SetTimeLte("2025-12-31 23:59:59"))
+		if err != nil {
+			t.Fatalf("unexpected error from Archive: %v", err)
+		}
+
+		if manifest.Count != 5 || !manifest.From.Equal(old) || !manifest.To.Equal(old) {
+			t.Fatalf("unexpected %s manifest: %+v", compression, manifest)
+		}
+
+		if manifest.Checksum == "" || manifest.Compression != compression.String() {
+			t.Fatalf("expected the manifest to have a checksum and compression, got %+v", manifest)
+		}
+
+		file := filepath.Join(directory, manifest.File)
+		if _, err := os.Stat(file); err != nil {
+			t.Fatalf("expected the archive file to exist: %v", err)
+		}
+
+		count, err := s.LogCount(ctx, LogQuery())
+		if err != nil {
+			t.Fatalf("unexpected error from LogCount: %v", err)
+		}
+
+		if count != 1 {
+			t.Fatalf("expected the archived logs to be deleted, got %d logs", count)
+		}
+
+		restored, err := archiver.Restore(ctx, file)
+		if err != nil {
+			t.Fatalf("unexpected error from Restore: %v", err)
+		}
+
+		if restored != 5 {
+			t.Fatalf("expected 5 logs to be restored, got %d", restored)
+		}
+
+		logs, err := s.LogList(ctx, LogQuery().SetMessageContains("old"))
+		if err != nil {
+			t.Fatalf("unexpected error from LogList: %v", err)
+		}
+
+		if len(logs) != 5 || !logs[0].GetTime().Equal(old) {
+			t.Fatalf("expected the restored logs to keep their time, got %v", logs)
+		}
+	}
+}
+
+func Test_Archiver_Restore_ChecksumMismatch(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_archive_checksum",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+
+	if err := s.Info("archived"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	directory := t.TempDir()
+
+	archiver, err := NewArchiver(ArchiverOptions{Store: s, Directory: directory})
+	if err != nil {
+		t.Fatalf("unexpected error from NewArchiver: %v", err)
+	}
+
+	manifest, err := archiver.Archive(ctx, LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from Archive: %v", err)
+	}
+
+	file := filepath.Join(directory, manifest.File)
+	if err := os.WriteFile(file, []byte("tampered"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := archiver.Restore(ctx, file); err == nil {
+		t.Fatal("expected an error restoring an archive not matching its manifest")
+	}
+}
+
+func Test_Archiver_Archive_NothingToArchive(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_archive_empty",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	directory := t.TempDir()
+
+	archiver, err := NewArchiver(ArchiverOptions{Store: s, Directory: directory})
+	if err != nil {
+		t.Fatalf("unexpected error from NewArchiver: %v", err)
+	}
+
+	manifest, err := archiver.Archive(context.Background(), LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from Archive: %v", err)
+	}
+
+	entries, _ := os.ReadDir(directory)
+	if manifest.Count != 0 || len(entries) != 0 {
+		t.Fatalf("expected no files for an empty archive, got %d", len(entries))
+	}
+}
+
+func Test_Archiver_DeleteArchived(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_archive_delete",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+	old := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	for range 5 {
+		if err := s.LogCreate(ctx, NewLog().SetLevel(LEVEL_INFO).SetMessage("old").SetTime(old)); err != nil {
+			t.Fatalf("unexpected error from LogCreate: %v", err)
+		}
+	}
+
+	directory := t.TempDir()
+
+	archiver, err := NewArchiver(ArchiverOptions{Store: s, Directory: directory, BatchSize: 2})
+	if err != nil {
+		t.Fatalf("unexpected error from NewArchiver: %v", err)
+	}
+
+	file := filepath.Join(directory, "archive.ndjson.gz")
+
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Archive(ctx, LogQuery(), f); err != nil {
+		t.Fatalf("unexpected error from Archive: %v", err)
+	}
+	f.Close()
+
+	// a late entry of the archived time range, added after the archive
+	if err := s.LogCreate(ctx, NewLog().SetLevel(LEVEL_INFO).SetMessage("late").SetTime(old)); err != nil {
+		t.Fatalf("unexpected error from LogCreate: %v", err)
+	}
+
+	if err := archiver.deleteArchived(ctx, file); err != nil {
+		t.Fatalf("unexpected error from deleteArchived: %v", err)
+	}
+
+	logs, err := s.LogList(ctx, LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	if len(logs) != 1 || logs[0].GetMessage() != "late" {
+		t.Fatalf("expected only the late log to be kept, got %v", logs)
+	}
+}
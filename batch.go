@@ -0,0 +1,110 @@
+package logstore
+
+import (
+	"context"
+	"time"
+)
+
+// defaultBatchSize is the number of rows read per query when iterating
+const defaultBatchSize = 500
+
+// LogForEachBatch calls fn with the logs matching the query filters, in
+// batches of batchSize (by default 500) ordered by time and ID, stopping at
+// the first error returned by fn. Batches are read by keyset pagination, so
+// large result sets are not held in memory, and fn may delete the logs it
+// receives. The limit, offset and order of the query are ignored.
+func (st *storeImplementation) LogForEachBatch(ctx context.Context, query LogQueryInterface, batchSize int, fn func(batch []LogInterface) error) error {
+	if query == nil {
+		query = LogQuery()
+	}
+
+	if err := query.Validate(); err != nil {
+		return err
+	}
+
+	return st.forEachLogBatch(ctx, query, batchSize, fn)
+}
+
+// forEachLogBatch calls fn with the logs matching the query filters, in
+// batches ordered by time and ID. It pages with the last time and ID seen
+// rather than an offset, so rows deleted by fn do not shift the pages.
+// The limit, offset and order of the query are ignored.
+func (st *storeImplementation) forEachLogBatch(ctx context.Context, query LogQueryInterface, batchSize int, fn func(batch []LogInterface) error) error {
+	return st.forEachLogBatchSince(ctx, query, time.Time{}, batchSize, fn)
+}
+
+// forEachLogBatchSince is forEachLogBatch, restricted to the logs at or
+// after since when it is not zero
+func (st *storeImplementation) forEachLogBatchSince(ctx context.Context, query LogQueryInterface, since time.Time, batchSize int, fn func(batch []LogInterface) error) error {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	if st.partitioning != PartitioningNone {
+		partitions, err := st.partitionsFor(query)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range partitions {
+			if !since.IsZero() && !p.end.After(since) {
+				continue
+			}
+
+			if err := st.partitionView(p.table).forEachLogBatchSince(ctx, query, since, batchSize, fn); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	var last LogInterface
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		q := st.buildFilterQuery(query)
+
+		if !since.IsZero() {
+			q = q.Where(COLUMN_TIME+" >= ?", toDateTimeString(since))
+		}
+
+		if last != nil {
+			lastTime := toDateTimeString(last.GetTime())
+			q = q.Where("("+COLUMN_TIME+" > ? OR ("+COLUMN_TIME+" = ? AND "+COLUMN_ID+" > ?))",
+				lastTime, lastTime, last.GetID())
+		}
+
+		var results []map[string]any
+		err := q.OrderBy(COLUMN_TIME, "asc").
+			OrderBy(COLUMN_ID, "asc").
+			Limit(batchSize).
+			Get(&results)
+
+		if err != nil {
+			return err
+		}
+
+		if len(results) == 0 {
+			return nil
+		}
+
+		batch := make([]LogInterface, 0, len(results))
+		for _, result := range results {
+			batch = append(batch, logFromResult(result))
+		}
+
+		if err := fn(batch); err != nil {
+			return err
+		}
+
+		if len(results) < batchSize {
+			return nil
+		}
+
+		last = batch[len(batch)-1]
+	}
+}
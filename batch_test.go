@@ -0,0 +1,72 @@
+package logstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Store_LogForEachBatch(t *testing.T) {
+	s, err := NewStore(NewStoreOptions{
+		DB:                 InitDB(),
+		LogTableName:       "log_batches",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+	at := time.Date(2026, time.October, 19, 14, 0, 0, 0, time.UTC)
+
+	// entries sharing a time are paged by ID
+	for i := range 5 {
+		logEntry := NewLog().SetLevel(LEVEL_INFO).SetMessage("entry").SetTime(at.Add(time.Duration(i/2) * time.Second))
+		if err := s.Log(logEntry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := s.Debug("filtered out"); err != nil {
+		t.Fatal(err)
+	}
+
+	sizes := []int{}
+	var last LogInterface
+
+	err = s.LogForEachBatch(ctx, LogQuery().SetLevel(LEVEL_INFO).SetLimit(1), 2, func(batch []LogInterface) error {
+		sizes = append(sizes, len(batch))
+
+		for _, logEntry := range batch {
+			if last != nil && (logEntry.GetTime().Before(last.GetTime()) ||
+				(logEntry.GetTime().Equal(last.GetTime()) && logEntry.GetID() <= last.GetID())) {
+				t.Fatalf("expected the logs ordered by time and ID")
+			}
+			last = logEntry
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error from LogForEachBatch: %v", err)
+	}
+
+	if len(sizes) != 3 || sizes[0] != 2 || sizes[1] != 2 || sizes[2] != 1 {
+		t.Fatalf("expected batches of 2, 2 and 1 logs, got %v", sizes)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+
+	err = s.LogForEachBatch(ctx, nil, 2, func(batch []LogInterface) error {
+		calls++
+		return stop
+	})
+
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Fatalf("expected the error of fn to stop the iteration, got %v after %d calls", err, calls)
+	}
+}
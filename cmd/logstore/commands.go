@@ -0,0 +1,477 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dracory/logstore"
+)
+
+// deleteBatchSize is the number of logs deleted per query by delete
+const deleteBatchSize = 500
+
+// migrate creates or drops the log table
+func (a *app) migrate(ctx context.Context, args []string) error {
+	flags := a.newFlagSet("migrate", "migrate up|down [flags]")
+	yes := flags.Bool("yes", false, "drop without asking for confirmation")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	direction := flags.Arg(0)
+	if direction != "up" && direction != "down" {
+		flags.Usage()
+		return errUsage
+	}
+
+	// flags may also follow the direction, i.e. "migrate down -yes"
+	if err := flags.Parse(flags.Args()[1:]); err != nil {
+		return err
+	}
+
+	store, closeStore, err := a.open()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	if direction == "up" {
+		if err := store.MigrateUp(ctx); err != nil {
+			return err
+		}
+		fmt.Fprintln(a.stdout, "Migrated up")
+		return nil
+	}
+
+	if !*yes && !a.confirm(fmt.Sprintf("Drop the log table %q?", a.table)) {
+		fmt.Fprintln(a.stdout, "Aborted")
+		return nil
+	}
+
+	if err := store.MigrateDown(ctx); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(a.stdout, "Migrated down")
+	return nil
+}
+
+// tail prints the most recent logs, then new logs as they arrive
+func (a *app) tail(ctx context.Context, args []string) error {
+	flags := a.newFlagSet("tail", "tail [flags]")
+	f := &filters{}
+	f.register(flags)
+	lines := flags.Int("n", 10, "number of recent logs printed first")
+	interval := flags.Duration("interval", time.Second, "how often to check for new logs")
+	output := flags.String("output", "text", "output format: text or json")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := f.validate(); err != nil {
+		return err
+	}
+
+	print, err := a.logPrinter(*output)
+	if err != nil {
+		return err
+	}
+
+	store, closeStore, err := a.open()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	// subscribing first, so no log written while the recent logs are
+	// listed is missed
+	logs, err := store.Subscribe(ctx, f.query(), logstore.SubscribeOptions{
+		PollInterval: *interval,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	printed := map[string]bool{}
+
+	if *lines > 0 {
+		recent, err := store.LogList(ctx, f.query().
+			SetOrderBy(logstore.COLUMN_TIME).
+			SetOrderDirection("desc").
+			SetLimit(*lines))
+
+		if err != nil {
+			return err
+		}
+
+		slices.Reverse(recent)
+
+		for _, logEntry := range recent {
+			print(logEntry)
+			printed[logEntry.GetID()] = true
+		}
+	}
+
+	for logEntry := range logs {
+		if printed[logEntry.GetID()] {
+			continue
+		}
+
+		print(logEntry)
+	}
+
+	return nil
+}
+
+// query lists the logs matching the filters
+func (a *app) query(ctx context.Context, args []string) error {
+	flags := a.newFlagSet("query", "query [flags]")
+	f := &filters{}
+	f.register(flags)
+	limit := flags.Int("limit", 100, "maximum number of logs")
+	offset := flags.Int("offset", 0, "number of logs to skip")
+	orderBy := flags.String("order-by", logstore.COLUMN_TIME, "column to order by")
+	orderDirection := flags.String("order-dir", "desc", "order direction: asc or desc")
+	output := flags.String("output", "table", "output format: table or json")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := f.validate(); err != nil {
+		return err
+	}
+
+	if !logstore.IsOrderColumn(*orderBy) {
+		fmt.Fprintf(a.stderr, "logstore: invalid -order-by %q\n", *orderBy)
+		flags.Usage()
+		return errUsage
+	}
+
+	*orderDirection = strings.ToLower(*orderDirection)
+	if *orderDirection != "asc" && *orderDirection != "desc" {
+		fmt.Fprintf(a.stderr, "logstore: invalid -order-dir %q\n", *orderDirection)
+		flags.Usage()
+		return errUsage
+	}
+
+	store, closeStore, err := a.open()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	logs, err := store.LogList(ctx, f.query().
+		SetLimit(*limit).
+		SetOffset(*offset).
+		SetOrderBy(*orderBy).
+		SetOrderDirection(*orderDirection))
+
+	if err != nil {
+		return err
+	}
+
+	switch *output {
+	case "table":
+		return writeLogTable(a.stdout, logs)
+	case "json":
+		return writeJSON(a.stdout, logRecords(logs))
+	}
+
+	return fmt.Errorf("unknown output %q", *output)
+}
+
+// count prints the number of logs matching the filters
+func (a *app) count(ctx context.Context, args []string) error {
+	flags := a.newFlagSet("count", "count [flags]")
+	f := &filters{}
+	f.register(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := f.validate(); err != nil {
+		return err
+	}
+
+	store, closeStore, err := a.open()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	count, err := store.LogCount(ctx, f.query())
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(a.stdout, count)
+	return nil
+}
+
+// delete deletes the logs matching the filters, after confirmation
+func (a *app) delete(ctx context.Context, args []string) error {
+	flags := a.newFlagSet("delete", "delete [flags]")
+	f := &filters{}
+	f.register(flags)
+	all := flags.Bool("all", false, "allow deleting without filters")
+	yes := flags.Bool("yes", false, "delete without asking for confirmation")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if f.isEmpty() && !*all {
+		return errors.New("refusing to delete without filters, use -all to delete every log")
+	}
+
+	if err := f.validate(); err != nil {
+		return err
+	}
+
+	store, closeStore, err := a.open()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	count, err := store.LogCount(ctx, f.query())
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		fmt.Fprintln(a.stdout, "No logs match")
+		return nil
+	}
+
+	if !*yes && !a.confirm(fmt.Sprintf("Delete %d logs?", count)) {
+		fmt.Fprintln(a.stdout, "Aborted")
+		return nil
+	}
+
+	var deleted int64
+	for deleted < count {
+		logs, err := store.LogList(ctx, f.query().SetLimit(deleteBatchSize))
+		if err != nil {
+			return err
+		}
+
+		if len(logs) == 0 {
+			break
+		}
+
+		ids := make([]string, 0, len(logs))
+		for _, logEntry := range logs {
+			ids = append(ids, logEntry.GetID())
+		}
+
+		if err := store.LogDeleteByIDs(ctx, ids); err != nil {
+			return err
+		}
+
+		deleted += int64(len(ids))
+	}
+
+	fmt.Fprintf(a.stdout, "Deleted %d logs\n", deleted)
+	return nil
+}
+
+// export writes the logs matching the filters to a file or stdout
+func (a *app) export(ctx context.Context, args []string) error {
+	flags := a.newFlagSet("export", "export [flags]")
+	f := &filters{}
+	f.register(flags)
+	format := flags.String("format", "ndjson", "export format: ndjson, csv or logfmt")
+	out := flags.String("out", "", "output file, by default stdout")
+	contextColumns := flags.String("context-columns", "", "comma separated context keys written as CSV columns")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := f.validate(); err != nil {
+		return err
+	}
+
+	exportFormat, err := parseExportFormat(*format)
+	if err != nil {
+		return err
+	}
+
+	options := logstore.ExportOptions{}
+	if *contextColumns != "" {
+		options.ContextColumns = strings.Split(*contextColumns, ",")
+	}
+
+	store, closeStore, err := a.open()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	if *out == "" {
+		return store.Export(ctx, f.query(), exportFormat, a.stdout, options)
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+
+	if err := store.Export(ctx, f.query(), exportFormat, file, options); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// importLogs imports log files from a file or stdin
+func (a *app) importLogs(ctx context.Context, args []string) error {
+	flags := a.newFlagSet("import", "import [flags]")
+	format := flags.String("format", "slog-json", "import format: slog-json, slog-text or ndjson")
+	in := flags.String("in", "", "input file, by default stdin")
+	batchSize := flags.Int("batch-size", 500, "number of logs written per insert")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	importFormat, err := parseImportFormat(*format)
+	if err != nil {
+		return err
+	}
+
+	store, closeStore, err := a.open()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	r := a.stdin
+	if *in != "" {
+		file, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	importer, err := logstore.NewImporter(logstore.ImporterOptions{
+		Store:     store,
+		Format:    importFormat,
+		BatchSize: *batchSize,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	result, err := importer.Import(ctx, r)
+
+	for _, lineError := range result.Errors {
+		fmt.Fprintln(a.stderr, lineError)
+	}
+
+	fmt.Fprintf(a.stdout, "Imported %d of %d lines\n", result.Imported, result.Lines)
+
+	if err != nil {
+		return err
+	}
+
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("%d lines could not be imported", len(result.Errors))
+	}
+
+	return nil
+}
+
+// stats summarises the logs matching the filters
+func (a *app) stats(ctx context.Context, args []string) error {
+	flags := a.newFlagSet("stats", "stats [flags]")
+	f := &filters{}
+	f.register(flags)
+	output := flags.String("output", "table", "output format: table or json")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := f.validate(); err != nil {
+		return err
+	}
+
+	store, closeStore, err := a.open()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	stats, err := store.LogStats(ctx, f.query())
+	if err != nil {
+		return err
+	}
+
+	switch *output {
+	case "table":
+		return writeStatsTable(a.stdout, stats)
+	case "json":
+		return writeJSON(a.stdout, stats)
+	}
+
+	return fmt.Errorf("unknown output %q", *output)
+}
+
+// confirm asks a yes/no question on stdout and reads the answer from stdin
+func (a *app) confirm(question string) bool {
+	fmt.Fprintf(a.stdout, "%s [y/N] ", question)
+
+	answer, err := bufio.NewReader(a.stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// parseExportFormat maps the -format flag of export to its option
+func parseExportFormat(value string) (logstore.ExportFormat, error) {
+	switch value {
+	case "ndjson":
+		return logstore.ExportFormatNDJSON, nil
+	case "csv":
+		return logstore.ExportFormatCSV, nil
+	case "logfmt":
+		return logstore.ExportFormatLogfmt, nil
+	}
+
+	return 0, fmt.Errorf("unknown export format %q", value)
+}
+
+// parseImportFormat maps the -format flag of import to its option
+func parseImportFormat(value string) (logstore.ImportFormat, error) {
+	switch value {
+	case "slog-json":
+		return logstore.ImportFormatSlogJSON, nil
+	case "slog-text":
+		return logstore.ImportFormatSlogText, nil
+	case "ndjson":
+		return logstore.ImportFormatNDJSON, nil
+	}
+
+	return 0, fmt.Errorf("unknown import format %q", value)
+}
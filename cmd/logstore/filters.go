@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dracory/logstore"
+)
+
+// filters holds the flags mapped to LogQueryInterface filters
+type filters struct {
+	id                 string
+	level              string
+	levelIn            string
+	messageContains    string
+	messageNotContains string
+	contextContains    string
+	since              time.Duration
+	from               string
+	to                 string
+	fingerprint        string
+	traceID            string
+	spanID             string
+	requestID          string
+	serviceName        string
+	environment        string
+	hostName           string
+	instanceID         string
+}
+
+// register adds the filter flags to the flag set
+func (f *filters) register(flags *flag.FlagSet) {
+	flags.StringVar(&f.id, "id", "", "log ID")
+	flags.StringVar(&f.level, "level", "", "level, i.e. error")
+	flags.StringVar(&f.levelIn, "levels", "", "comma separated levels, i.e. error,fatal")
+	flags.StringVar(&f.messageContains, "message", "", "message contains")
+	flags.StringVar(&f.messageNotContains, "not-message", "", "message does not contain")
+	flags.StringVar(&f.contextContains, "context", "", "context contains")
+	flags.DurationVar(&f.since, "since", 0, "logs of the last duration, i.e. 1h")
+	flags.StringVar(&f.from, "from", "", `logs at or after the time, i.e. "2026-10-19 14:00:00"`)
+	flags.StringVar(&f.to, "to", "", `logs at or before the time, i.e. "2026-10-19 15:00:00"`)
+	flags.StringVar(&f.fingerprint, "fingerprint", "", "issue fingerprint")
+	flags.StringVar(&f.traceID, "trace-id", "", "trace ID")
+	flags.StringVar(&f.spanID, "span-id", "", "span ID")
+	flags.StringVar(&f.requestID, "request-id", "", "request ID")
+	flags.StringVar(&f.serviceName, "service", "", "service name")
+	flags.StringVar(&f.environment, "env", "", "environment")
+	flags.StringVar(&f.hostName, "host", "", "host name")
+	flags.StringVar(&f.instanceID, "instance", "", "instance ID")
+}
+
+// isEmpty reports whether no filter is set
+func (f *filters) isEmpty() bool {
+	return *f == filters{}
+}
+
+// query builds a new query from the filters
+func (f *filters) query() logstore.LogQueryInterface {
+	query := logstore.LogQuery()
+
+	if f.id != "" {
+		query.SetID(f.id)
+	}
+
+	if f.level != "" {
+		query.SetLevel(f.level)
+	}
+
+	if f.levelIn != "" {
+		query.SetLevelIn(strings.Split(f.levelIn, ","))
+	}
+
+	if f.messageContains != "" {
+		query.SetMessageContains(f.messageContains)
+	}
+
+	if f.messageNotContains != "" {
+		query.SetMessageNotContains(f.messageNotContains)
+	}
+
+	if f.contextContains != "" {
+		query.SetContextContains(f.contextContains)
+	}
+
+	if f.since > 0 {
+		query.SetTimeGte(time.Now().UTC().Add(-f.since).Format(time.DateTime))
+	}
+
+	if f.from != "" {
+		query.SetTimeGte(f.from)
+	}
+
+	if f.to != "" {
+		query.SetTimeLte(f.to)
+	}
+
+	if f.fingerprint != "" {
+		query.SetFingerprint(f.fingerprint)
+	}
+
+	if f.traceID != "" {
+		query.SetTraceID(f.traceID)
+	}
+
+	if f.spanID != "" {
+		query.SetSpanID(f.spanID)
+	}
+
+	if f.requestID != "" {
+		query.SetRequestID(f.requestID)
+	}
+
+	if f.serviceName != "" {
+		query.SetServiceName(f.serviceName)
+	}
+
+	if f.environment != "" {
+		query.SetEnvironment(f.environment)
+	}
+
+	if f.hostName != "" {
+		query.SetHostName(f.hostName)
+	}
+
+	if f.instanceID != "" {
+		query.SetInstanceID(f.instanceID)
+	}
+
+	return query
+}
+
+// validate checks the filters build a valid query
+func (f *filters) validate() error {
+	if err := f.query().Validate(); err != nil {
+		return fmt.Errorf("invalid filters: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+// Command logstore runs operational tasks against a log store: migrations,
+// tailing, querying, counting, deleting, exporting, importing and stats.
+//
+// Usage:
+//
+//	logstore -driver sqlite -dsn ./app.db -table log <command> [flags]
+//
+// The driver, DSN and table can also be set with the LOGSTORE_DRIVER,
+// LOGSTORE_DSN and LOGSTORE_TABLE environment variables.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/dracory/logstore"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+const usage = `Usage: logstore [global flags] <command> [flags]
+
+Commands:
+  migrate up|down   create or drop the log table
+  tail              print new logs as they arrive
+  query             list logs matching the filters
+  count             count logs matching the filters
+  delete            delete logs by ID or filters, after confirmation
+  export            export logs as ndjson, csv or logfmt
+  import            import slog json, slog text or ndjson files
+  stats             summarise logs matching the filters
+
+Run "logstore <command> -h" for the flags of a command.
+
+Global flags:
+`
+
+// app holds the streams and options shared by the commands
+type app struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	driver       string
+	dsn          string
+	table        string
+	tenant       string
+	partitioning string
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run parses the arguments, runs the command and returns the exit code
+func run(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	a := &app{stdin: stdin, stdout: stdout, stderr: stderr}
+
+	flags := flag.NewFlagSet("logstore", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(&a.driver, "driver", os.Getenv("LOGSTORE_DRIVER"), "database driver: sqlite, mysql or postgres")
+	flags.StringVar(&a.dsn, "dsn", os.Getenv("LOGSTORE_DSN"), "database data source name")
+	flags.StringVar(&a.table, "table", envOr("LOGSTORE_TABLE", "log"), "log table name")
+	flags.StringVar(&a.tenant, "tenant", "", "scope every command to the tenant")
+	flags.StringVar(&a.partitioning, "partitioning", "", "table partitioning: daily, weekly or monthly")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	commands := map[string]func(ctx context.Context, args []string) error{
+		"migrate": a.migrate,
+		"tail":    a.tail,
+		"query":   a.query,
+		"count":   a.count,
+		"delete":  a.delete,
+		"export":  a.export,
+		"import":  a.importLogs,
+		"stats":   a.stats,
+	}
+
+	command, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "logstore: unknown command %q\n", flags.Arg(0))
+		flags.Usage()
+		return 2
+	}
+
+	if err := command(ctx, flags.Args()[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		if errors.Is(err, errUsage) {
+			return 2
+		}
+		fmt.Fprintf(stderr, "logstore: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// errUsage is returned by commands after printing a usage error
+var errUsage = errors.New("usage")
+
+// open opens the database and the log store, without migrating
+func (a *app) open() (logstore.StoreInterface, func(), error) {
+	if a.driver == "" || a.dsn == "" {
+		return nil, nil, errors.New("-driver and -dsn are required")
+	}
+
+	driver := a.driver
+	if driver == "postgresql" {
+		driver = "postgres"
+	}
+
+	partitioning, err := parsePartitioning(a.partitioning)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	db, err := sql.Open(driver, a.dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	store, err := logstore.NewStore(logstore.NewStoreOptions{
+		DB:           db,
+		LogTableName: a.table,
+		Partitioning: partitioning,
+	})
+
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+
+	if a.tenant != "" {
+		store = store.ForTenant(a.tenant)
+	}
+
+	return store, func() { db.Close() }, nil
+}
+
+// parsePartitioning maps the -partitioning flag to its option
+func parsePartitioning(value string) (logstore.Partitioning, error) {
+	switch strings.ToLower(value) {
+	case "", "none":
+		return logstore.PartitioningNone, nil
+	case "daily":
+		return logstore.PartitioningDaily, nil
+	case "weekly":
+		return logstore.PartitioningWeekly, nil
+	case "monthly":
+		return logstore.PartitioningMonthly, nil
+	}
+
+	return logstore.PartitioningNone, fmt.Errorf("unknown partitioning %q", value)
+}
+
+// newFlagSet creates the flag set of a command
+func (a *app) newFlagSet(name string, usage string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: logstore %s\n\nFlags:\n", usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// envOr returns the environment variable, or the fallback when it is empty
+func envOr(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
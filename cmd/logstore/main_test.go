@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dracory/logstore"
+)
+
+// runCommand runs the CLI against the database file, returning the exit
+// code and output
+func runCommand(t *testing.T, dsn string, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	globalArgs := []string{"-driver", "sqlite", "-dsn", dsn, "-table", "log_cli"}
+	code := run(context.Background(), append(globalArgs, args...), strings.NewReader(stdin), stdout, stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+func Test_CLI(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "logs.db")
+
+	if code, _, stderr := runCommand(t, dsn, "", "migrate", "up"); code != 0 {
+		t.Fatalf("migrate up failed: %s", stderr)
+	}
+
+	input := `{"time":"2026-10-19T14:00:00Z","level":"INFO","msg":"user signed in","user_id":"u-1"}
+{"time":"2026-10-19T14:05:00Z","level":"ERROR","msg":"payment failed","user_id":"u-2"}
+{"time":"2026-10-19T14:10:00Z","level":"ERROR","msg":"payment failed","user_id":"u-3"}
+`
+
+	importFile := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(importFile, []byte(input), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, stdout, stderr := runCommand(t, dsn, "", "import", "-in", importFile)
+	if code != 0 || !strings.Contains(stdout, "Imported 3 of 3 lines") {
+		t.Fatalf("import failed: %s %s", stdout, stderr)
+	}
+
+	code, stdout, _ = runCommand(t, dsn, "", "count", "-level", "error")
+	if code != 0 || strings.TrimSpace(stdout) != "2" {
+		t.Fatalf("expected 2 error logs, got %q", stdout)
+	}
+
+	code, stdout, _ = runCommand(t, dsn, "", "query", "-output", "json", "-order-dir", "asc", "-context", "u-1")
+	if code != 0 {
+		t.Fatalf("query failed: %s", stdout)
+	}
+
+	records := []logstore.LogRecord{}
+	if err := json.Unmarshal([]byte(stdout), &records); err != nil {
+		t.Fatalf("expected JSON output: %v", err)
+	}
+
+	if len(records) != 1 || records[0].Message != "user signed in" {
+		t.Fatalf("unexpected query result: %+v", records)
+	}
+
+	if code, _, stderr := runCommand(t, dsn, "", "query", "-order-by", "password"); code != 2 || !strings.Contains(stderr, `invalid -order-by "password"`) {
+		t.Fatalf("expected an invalid order column to be a usage error, got %d %q", code, stderr)
+	}
+
+	if code, _, stderr := runCommand(t, dsn, "", "query", "-order-dir", "sideways"); code != 2 || !strings.Contains(stderr, `invalid -order-dir "sideways"`) {
+		t.Fatalf("expected an invalid order direction to be a usage error, got %d %q", code, stderr)
+	}
+
+	code, stdout, _ = runCommand(t, dsn, "", "query", "-levels", "error,info")
+	if code != 0 || !strings.Contains(stdout, "MESSAGE") || strings.Count(stdout, "\n") != 4 {
+		t.Fatalf("expected a table with a header and 3 rows, got %q", stdout)
+	}
+
+	code, stdout, _ = runCommand(t, dsn, "", "stats", "-output", "json")
+	if code != 0 {
+		t.Fatalf("stats failed: %s", stdout)
+	}
+
+	stats := logstore.Stats{}
+	if err := json.Unmarshal([]byte(stdout), &stats); err != nil {
+		t.Fatalf("expected JSON output: %v", err)
+	}
+
+	if stats.Count != 3 || stats.CountByLevel[logstore.LEVEL_ERROR] != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	code, stdout, _ = runCommand(t, dsn, "", "export", "-format", "csv", "-context-columns", "user_id")
+	if code != 0 || !strings.Contains(stdout, ",u-2,") {
+		t.Fatalf("expected the CSV export to have the user_id column, got %q", stdout)
+	}
+
+	if code, _, _ := runCommand(t, dsn, "", "delete"); code != 1 {
+		t.Fatal("expected delete without filters to be refused")
+	}
+
+	code, stdout, _ = runCommand(t, dsn, "n\n", "delete", "-level", "error")
+	if code != 0 || !strings.Contains(stdout, "Aborted") {
+		t.Fatalf("expected the delete to be aborted, got %q", stdout)
+	}
+
+	code, stdout, _ = runCommand(t, dsn, "y\n", "delete", "-level", "error")
+	if code != 0 || !strings.Contains(stdout, "Deleted 2 logs") {
+		t.Fatalf("expected 2 logs to be deleted, got %q", stdout)
+	}
+
+	code, stdout, _ = runCommand(t, dsn, "", "count")
+	if code != 0 || strings.TrimSpace(stdout) != "1" {
+		t.Fatalf("expected 1 log left, got %q", stdout)
+	}
+
+	if code, _, _ := runCommand(t, dsn, "", "unknown"); code != 2 {
+		t.Fatal("expected an unknown command to be a usage error")
+	}
+
+	if code, stdout, _ := runCommand(t, dsn, "", "migrate", "down", "-yes"); code != 0 || !strings.Contains(stdout, "Migrated down") {
+		t.Fatalf("migrate down failed: %s", stdout)
+	}
+}
+
+func Test_CLI_Tail(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "logs.db")
+
+	if code, _, stderr := runCommand(t, dsn, "", "migrate", "up"); code != 0 {
+		t.Fatalf("migrate up failed: %s", stderr)
+	}
+
+	stdout := &syncBuffer{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan int)
+
+	go func() {
+		args := []string{"-driver", "sqlite", "-dsn", dsn, "-table", "log_cli", "tail", "-interval", "20ms"}
+		done <- run(ctx, args, strings.NewReader(""), stdout, &bytes.Buffer{})
+	}()
+
+	input := `{"time":"` + time.Now().UTC().Add(time.Second).Format(time.RFC3339) + `","level":"INFO","msg":"arrived"}` + "\n"
+
+	// wait for tail to start, so the entry is new to it
+	time.Sleep(100 * time.Millisecond)
+
+	if code, stdout, stderr := runCommand(t, dsn, input, "import"); code != 0 {
+		t.Fatalf("import failed: %s %s", stdout, stderr)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(stdout.String(), "arrived") && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	cancel()
+
+	if code := <-done; code != 0 {
+		t.Fatalf("expected tail to exit cleanly, got %d", code)
+	}
+
+	if strings.Count(stdout.String(), "arrived") != 1 {
+		t.Fatalf("expected the new log to be printed once, got %q", stdout.String())
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes and reads
+type syncBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.String()
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dracory/logstore"
+)
+
+// maxContextWidth is the number of context characters shown in tables
+const maxContextWidth = 80
+
+// logPrinter returns a function printing one log per line, as text or JSON
+func (a *app) logPrinter(output string) (func(logEntry logstore.LogInterface), error) {
+	switch output {
+	case "text":
+		return func(logEntry logstore.LogInterface) {
+			line := fmt.Sprintf("%s %-7s %s",
+				logEntry.GetTime().UTC().Format(time.RFC3339),
+				strings.ToUpper(logEntry.GetLevel()),
+				logEntry.GetMessage())
+
+			if logEntry.GetContext() != "" {
+				line += " " + logEntry.GetContext()
+			}
+
+			fmt.Fprintln(a.stdout, line)
+		}, nil
+	case "json":
+		encoder := json.NewEncoder(a.stdout)
+		return func(logEntry logstore.LogInterface) {
+			encoder.Encode(logstore.NewLogRecord(logEntry))
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown output %q", output)
+}
+
+// writeLogTable writes the logs as aligned columns
+func writeLogTable(w io.Writer, logs []logstore.LogInterface) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "TIME\tLEVEL\tID\tMESSAGE\tCONTEXT")
+
+	for _, logEntry := range logs {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n",
+			logEntry.GetTime().UTC().Format(time.RFC3339),
+			logEntry.GetLevel(),
+			logEntry.GetID(),
+			singleLine(logEntry.GetMessage()),
+			truncate(singleLine(logEntry.GetContext()), maxContextWidth))
+	}
+
+	return table.Flush()
+}
+
+// writeStatsTable writes the stats as aligned columns
+func writeStatsTable(w io.Writer, stats logstore.Stats) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(table, "Count\t%d\n", stats.Count)
+
+	if !stats.Oldest.IsZero() {
+		fmt.Fprintf(table, "Oldest\t%s\n", stats.Oldest.UTC().Format(time.RFC3339))
+		fmt.Fprintf(table, "Newest\t%s\n", stats.Newest.UTC().Format(time.RFC3339))
+	}
+
+	levels := make([]string, 0, len(stats.CountByLevel))
+	for level := range stats.CountByLevel {
+		levels = append(levels, level)
+	}
+	slices.Sort(levels)
+
+	for _, level := range levels {
+		fmt.Fprintf(table, "Level %s\t%d\n", level, stats.CountByLevel[level])
+	}
+
+	return table.Flush()
+}
+
+// writeJSON writes the value as indented JSON
+func writeJSON(w io.Writer, value any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// logRecords converts the logs to their JSON representation
+func logRecords(logs []logstore.LogInterface) []logstore.LogRecord {
+	records := make([]logstore.LogRecord, 0, len(logs))
+	for _, logEntry := range logs {
+		records = append(records, logstore.NewLogRecord(logEntry))
+	}
+	return records
+}
+
+// singleLine replaces line breaks and tabs, which would break table columns
+func singleLine(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ", "\t", " ").Replace(s)
+}
+
+// truncate shortens s to at most max characters
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-3]) + "..."
+}
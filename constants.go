@@ -1,10 +1,23 @@
 package logstore
 
 const COLUMN_CONTEXT = "context"
+const COLUMN_ENVIRONMENT = "environment"
+const COLUMN_FINGERPRINT = "fingerprint"
+const COLUMN_HOST_NAME = "host_name"
 const COLUMN_ID = "id"
+const COLUMN_INSTANCE_ID = "instance_id"
+const COLUMN_LAST_SEEN = "last_seen"
 const COLUMN_LEVEL = "level"
 const COLUMN_MESSAGE = "message"
+const COLUMN_OCCURRENCES = "occurrences"
+const COLUMN_REQUEST_ID = "request_id"
+const COLUMN_SERVICE_NAME = "service_name"
+const COLUMN_SERVICE_VERSION = "service_version"
+const COLUMN_SPAN_ID = "span_id"
+const COLUMN_STACK = "stack"
+const COLUMN_TENANT_ID = "tenant_id"
 const COLUMN_TIME = "time"
+const COLUMN_TRACE_ID = "trace_id"
 
 // Log levels
 const (
@@ -0,0 +1,70 @@
+package logstore
+
+import "context"
+
+// CorrelationIDs holds the IDs used to join log entries across services
+type CorrelationIDs struct {
+	TraceID   string
+	SpanID    string
+	RequestID string
+}
+
+// CorrelationExtractor returns the correlation IDs carried by a context,
+// i.e. to read them from an OpenTelemetry span or a framework's request ID
+type CorrelationExtractor func(ctx context.Context) CorrelationIDs
+
+// correlationContextKey is the context key of the IDs set with ContextWithCorrelationIDs
+type correlationContextKey struct{}
+
+// ContextWithCorrelationIDs returns a copy of ctx carrying the IDs. The
+// non-empty IDs replace those already carried by ctx, the empty ones keep them.
+func ContextWithCorrelationIDs(ctx context.Context, ids CorrelationIDs) context.Context {
+	existing := CorrelationIDsFromContext(ctx)
+
+	if ids.TraceID == "" {
+		ids.TraceID = existing.TraceID
+	}
+
+	if ids.SpanID == "" {
+		ids.SpanID = existing.SpanID
+	}
+
+	if ids.RequestID == "" {
+		ids.RequestID = existing.RequestID
+	}
+
+	return context.WithValue(ctx, correlationContextKey{}, ids)
+}
+
+// CorrelationIDsFromContext returns the IDs set with ContextWithCorrelationIDs.
+// It is the default CorrelationExtractor.
+func CorrelationIDsFromContext(ctx context.Context) CorrelationIDs {
+	if ctx == nil {
+		return CorrelationIDs{}
+	}
+
+	ids, _ := ctx.Value(correlationContextKey{}).(CorrelationIDs)
+	return ids
+}
+
+// applyCorrelationIDs sets the correlation IDs of the entry from ctx,
+// keeping any ID the entry already has
+func (st *storeImplementation) applyCorrelationIDs(ctx context.Context, logEntry LogInterface) {
+	if ctx == nil || st.correlationExtractor == nil {
+		return
+	}
+
+	ids := st.correlationExtractor(ctx)
+
+	if logEntry.GetTraceID() == "" && ids.TraceID != "" {
+		logEntry.SetTraceID(ids.TraceID)
+	}
+
+	if logEntry.GetSpanID() == "" && ids.SpanID != "" {
+		logEntry.SetSpanID(ids.SpanID)
+	}
+
+	if logEntry.GetRequestID() == "" && ids.RequestID != "" {
+		logEntry.SetRequestID(ids.RequestID)
+	}
+}
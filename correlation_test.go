@@ -0,0 +1,108 @@
+package logstore
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_ContextWithCorrelationIDs(t *testing.T) {
+	ctx := ContextWithCorrelationIDs(context.Background(), CorrelationIDs{RequestID: "r-1"})
+	ctx = ContextWithCorrelationIDs(ctx, CorrelationIDs{TraceID: "t-1", SpanID: "s-1"})
+
+	ids := CorrelationIDsFromContext(ctx)
+	if ids.TraceID != "t-1" || ids.SpanID != "s-1" || ids.RequestID != "r-1" {
+		t.Fatalf("unexpected correlation IDs: %+v", ids)
+	}
+
+	if CorrelationIDsFromContext(context.Background()) != (CorrelationIDs{}) {
+		t.Fatal("expected no correlation IDs in an empty context")
+	}
+}
+
+func Test_Store_CorrelationIDs(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_correlation",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := ContextWithCorrelationIDs(context.Background(), CorrelationIDs{
+		TraceID:   "trace-1",
+		SpanID:    "span-1",
+		RequestID: "request-1",
+	})
+
+	if err := s.LogCtx(ctx, NewLog().SetMessage("from context")); err != nil {
+		t.Fatalf("unexpected error from LogCtx: %v", err)
+	}
+
+	if err := s.LogCreate(ctx, NewLog().SetMessage("explicit").SetRequestID("request-2")); err != nil {
+		t.Fatalf("unexpected error from LogCreate: %v", err)
+	}
+
+	if err := s.Info("no context"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	logs, err := s.LogList(context.Background(), LogQuery().SetTraceID("trace-1"))
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 logs for the trace, got %d", len(logs))
+	}
+
+	logs, err = s.LogList(context.Background(), LogQuery().SetRequestID("request-2"))
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	if len(logs) != 1 || logs[0].GetSpanID() != "span-1" || logs[0].GetTraceID() != "trace-1" {
+		t.Fatal("expected the explicit request ID to be kept and the others taken from the context")
+	}
+
+	if _, err := s.LogList(context.Background(), LogQuery().SetSpanID("")); err == nil {
+		t.Fatal("expected error for empty span_id, got nil")
+	}
+}
+
+func Test_Store_CorrelationExtractor(t *testing.T) {
+	type tenantKey struct{}
+
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_correlation_extractor",
+		AutomigrateEnabled: true,
+		CorrelationExtractor: func(ctx context.Context) CorrelationIDs {
+			id, _ := ctx.Value(tenantKey{}).(string)
+			return CorrelationIDs{RequestID: id}
+		},
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "custom-1")
+	if err := s.LogCtx(ctx, NewLog().SetMessage("custom")); err != nil {
+		t.Fatalf("unexpected error from LogCtx: %v", err)
+	}
+
+	count, err := s.LogCount(context.Background(), LogQuery().SetRequestID("custom-1"))
+	if err != nil {
+		t.Fatalf("unexpected error from LogCount: %v", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("expected 1 log with the extracted request ID, got %d", count)
+	}
+}
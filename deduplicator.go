@@ -0,0 +1,141 @@
+package logstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// deduplicator collapses identical log entries written within a window
+// into a single entry, which is written when the window closes
+type deduplicator struct {
+	window  time.Duration
+	mutex   sync.Mutex
+	pending map[string]*pendingEntry
+}
+
+// pendingEntry is a log entry held back while its window is open
+type pendingEntry struct {
+	store    *storeImplementation
+	logEntry LogInterface
+	timer    *time.Timer
+}
+
+func newDeduplicator(window time.Duration) *deduplicator {
+	return &deduplicator{
+		window:  window,
+		pending: map[string]*pendingEntry{},
+	}
+}
+
+// add holds the entry back, or folds it into an identical pending entry
+// by bumping its occurrence count and last seen time
+func (d *deduplicator) add(store *storeImplementation, logEntry LogInterface) {
+	key := deduplicationKey(logEntry)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if pending, ok := d.pending[key]; ok {
+		pending.logEntry.SetOccurrences(pending.logEntry.GetOccurrences() + logEntry.GetOccurrences())
+		if logEntry.GetTime().After(pending.logEntry.GetLastSeen()) {
+			pending.logEntry.SetLastSeen(logEntry.GetTime())
+		}
+		return
+	}
+
+	if logEntry.GetOccurrences() < 1 {
+		logEntry.SetOccurrences(1)
+	}
+
+	if logEntry.GetLastSeen().IsZero() {
+		logEntry.SetLastSeen(logEntry.GetTime())
+	}
+
+	pending := &pendingEntry{
+		store:    store,
+		logEntry: logEntry,
+	}
+
+	pending.timer = time.AfterFunc(d.window, func() {
+		d.expire(key, pending)
+	})
+
+	d.pending[key] = pending
+}
+
+// expire writes a pending entry once its window has closed
+func (d *deduplicator) expire(key string, pending *pendingEntry) {
+	d.mutex.Lock()
+	if d.pending[key] != pending {
+		// already flushed
+		d.mutex.Unlock()
+		return
+	}
+	delete(d.pending, key)
+	d.mutex.Unlock()
+
+	if err := pending.store.insertLog(context.Background(), pending.logEntry); err != nil {
+		pending.store.logger.Error("deduplicator: writing log entry failed", "error", err)
+	}
+}
+
+// flush writes all pending entries without waiting for their windows to close
+func (d *deduplicator) flush(ctx context.Context) error {
+	d.mutex.Lock()
+	pendingList := make([]*pendingEntry, 0, len(d.pending))
+	for key, pending := range d.pending {
+		pending.timer.Stop()
+		pendingList = append(pendingList, pending)
+		delete(d.pending, key)
+	}
+	d.mutex.Unlock()
+
+	// keep the original write order
+	sort.Slice(pendingList, func(i, j int) bool {
+		return pendingList[i].logEntry.GetTime().Before(pendingList[j].logEntry.GetTime())
+	})
+
+	var errs []error
+	for _, pending := range pendingList {
+		if err := pending.store.insertLog(ctx, pending.logEntry); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// deduplicationKey identifies identical entries by tenant, level, message
+// and the names of the top level context keys
+func deduplicationKey(logEntry LogInterface) string {
+	return logEntry.GetTenantID() + "\x00" +
+		logEntry.GetLevel() + "\x00" +
+		logEntry.GetMessage() + "\x00" +
+		contextKeysFingerprint(logEntry.GetContext())
+}
+
+// contextKeysFingerprint returns the sorted top level keys of a JSON object
+// context, or an empty string if the context is not a JSON object
+func contextKeysFingerprint(context string) string {
+	if context == "" {
+		return ""
+	}
+
+	object := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(context), &object); err != nil {
+		return ""
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return strings.Join(keys, ",")
+}
@@ -0,0 +1,120 @@
+package logstore
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_Deduplicator_CollapsesIdenticalEntries(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                  db,
+		LogTableName:        "log_dedup",
+		AutomigrateEnabled:  true,
+		DeduplicationWindow: time.Hour,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := s.ErrorWithContext("db timeout", map[string]any{"attempt": i}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := s.ErrorWithContext("db timeout", map[string]any{"other": 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := s.Error("db timeout"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	count, err := s.LogCount(ctx, LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogCount: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected entries to be held back until flush, got %d rows", count)
+	}
+
+	if err := s.Flush(ctx); err != nil {
+		t.Fatalf("unexpected error from Flush: %v", err)
+	}
+
+	logs, err := s.LogList(ctx, LogQuery().SetContextContains("attempt"))
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	if len(logs) != 1 {
+		t.Fatalf("expected 1 collapsed entry, got %d", len(logs))
+	}
+
+	if logs[0].GetOccurrences() != 3 {
+		t.Fatalf("expected 3 occurrences, got %d", logs[0].GetOccurrences())
+	}
+
+	if logs[0].GetLastSeen().Before(logs[0].GetTime()) {
+		t.Fatal("expected last seen not to be before first seen")
+	}
+
+	count, err = s.LogCount(ctx, LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogCount: %v", err)
+	}
+	if count != 3 {
+		t.Fatalf("expected 3 rows (different context keys are not collapsed), got %d", count)
+	}
+}
+
+func Test_Deduplicator_WritesWhenWindowCloses(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                  db,
+		LogTableName:        "log_dedup_window",
+		AutomigrateEnabled:  true,
+		DeduplicationWindow: 20 * time.Millisecond,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	if err := s.Warn("disk almost full"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Warn("disk almost full"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	deadline := time.Now().Add(2 * time.Second)
+
+	for {
+		logs, err := s.LogList(ctx, LogQuery())
+		if err != nil {
+			t.Fatalf("unexpected error from LogList: %v", err)
+		}
+
+		if len(logs) == 1 {
+			if logs[0].GetOccurrences() != 2 {
+				t.Fatalf("expected 2 occurrences, got %d", logs[0].GetOccurrences())
+			}
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 1 entry after the window closed, got %d", len(logs))
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,44 @@
+package logstore
+
+import "fmt"
+
+// errorCause describes one error in a wrapped error chain
+type errorCause struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+// errorCauses returns the errors wrapped by err, depth first, following
+// both Unwrap() error (%w) and Unwrap() []error (errors.Join)
+func errorCauses(err error) []errorCause {
+	causes := []errorCause{}
+
+	var walk func(err error)
+	walk = func(err error) {
+		var wrapped []error
+
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			if inner := e.Unwrap(); inner != nil {
+				wrapped = []error{inner}
+			}
+		case interface{ Unwrap() []error }:
+			wrapped = e.Unwrap()
+		}
+
+		for _, inner := range wrapped {
+			if inner == nil {
+				continue
+			}
+			causes = append(causes, errorCause{
+				Message: inner.Error(),
+				Type:    fmt.Sprintf("%T", inner),
+			})
+			walk(inner)
+		}
+	}
+
+	walk(err)
+
+	return causes
+}
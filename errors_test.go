@@ -0,0 +1,88 @@
+package logstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+)
+
+func Test_ErrorCauses(t *testing.T) {
+	base := &fs.PathError{Op: "open", Path: "/tmp/x", Err: fs.ErrNotExist}
+	joined := errors.Join(fmt.Errorf("loading config: %w", base), errors.New("second"))
+
+	causes := errorCauses(joined)
+
+	// *fmt.wrapError, *fs.PathError, fs.ErrNotExist, *errors.errorString
+	if len(causes) != 4 {
+		t.Fatalf("expected 4 causes, got %d: %v", len(causes), causes)
+	}
+
+	if causes[1].Type != "*fs.PathError" {
+		t.Fatalf("expected the second cause to be *fs.PathError, got %s", causes[1].Type)
+	}
+
+	if causes[3].Message != "second" {
+		t.Fatalf("expected the last cause to be the joined error, got %s", causes[3].Message)
+	}
+}
+
+func Test_Store_ErrorErr(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_error_err",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	cause := errors.New("connection refused")
+	if err := s.ErrorErr(fmt.Errorf("query users: %w", cause), "user_id", 42); err != nil {
+		t.Fatalf("unexpected error from ErrorErr: %v", err)
+	}
+
+	logs, err := s.LogList(context.Background(), LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	if len(logs) != 1 {
+		t.Fatalf("expected 1 log, got %d", len(logs))
+	}
+
+	if logs[0].GetLevel() != LEVEL_ERROR || logs[0].GetMessage() != "query users: connection refused" {
+		t.Fatalf("unexpected log: %s %s", logs[0].GetLevel(), logs[0].GetMessage())
+	}
+
+	context := map[string]any{}
+	if err := json.Unmarshal([]byte(logs[0].GetContext()), &context); err != nil {
+		t.Fatalf("expected JSON context, got %q", logs[0].GetContext())
+	}
+
+	if context["user_id"] != float64(42) {
+		t.Fatalf("expected user_id attribute, got %v", context["user_id"])
+	}
+
+	if context["error_type"] != "*fmt.wrapError" {
+		t.Fatalf("expected error_type *fmt.wrapError, got %v", context["error_type"])
+	}
+
+	causes, ok := context["causes"].([]any)
+	if !ok || len(causes) != 1 {
+		t.Fatalf("expected 1 cause, got %v", context["causes"])
+	}
+
+	if err := s.ErrorErr(errors.New("x"), "odd"); err == nil {
+		t.Fatal("expected error for odd number of attributes, got nil")
+	}
+
+	if err := s.ErrorErr(nil); err == nil {
+		t.Fatal("expected error for nil error, got nil")
+	}
+}
@@ -0,0 +1,319 @@
+package logstore
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ExportFormat defines the format written by Export
+type ExportFormat int
+
+const (
+	// ExportFormatNDJSON writes one LogRecord JSON object per line
+	ExportFormatNDJSON ExportFormat = iota
+
+	// ExportFormatCSV writes a header row, then one row per log
+	ExportFormatCSV
+
+	// ExportFormatLogfmt writes one key=value line per log, with the
+	// context flattened into keys. Context keys named like the fixed keys,
+	// i.e. "time" or "msg", are prefixed with "context.".
+	ExportFormatLogfmt
+)
+
+// ExportOptions define the options for exporting logs
+type ExportOptions struct {
+	// ContextColumns lists the context keys written as CSV columns of
+	// their own, after the fixed columns. Nested keys are joined with
+	// dots, i.e. "http.status". The full context is kept in the last column.
+	ContextColumns []string
+
+	// BatchSize is the number of rows read per query, by default 500
+	BatchSize int
+}
+
+// exportCSVColumns are the fixed columns of a CSV export
+var exportCSVColumns = []string{
+	COLUMN_ID,
+	COLUMN_TIME,
+	COLUMN_LEVEL,
+	COLUMN_MESSAGE,
+	COLUMN_TRACE_ID,
+	COLUMN_SPAN_ID,
+	COLUMN_REQUEST_ID,
+	COLUMN_SERVICE_NAME,
+	COLUMN_HOST_NAME,
+	COLUMN_ENVIRONMENT,
+	COLUMN_TENANT_ID,
+}
+
+// Export writes the logs matching the query filters to w, oldest first.
+// Rows are read in batches and written as they are read, so the memory
+// used does not grow with the size of the export. The limit, offset and
+// order of the query are ignored.
+func (st *storeImplementation) Export(ctx context.Context, query LogQueryInterface, format ExportFormat, w io.Writer, opts ...ExportOptions) error {
+	if query == nil {
+		query = LogQuery()
+	}
+
+	if err := query.Validate(); err != nil {
+		return err
+	}
+
+	options := ExportOptions{}
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	switch format {
+	case ExportFormatNDJSON:
+		return st.exportNDJSON(ctx, query, w, options)
+	case ExportFormatCSV:
+		return st.exportCSV(ctx, query, w, options)
+	case ExportFormatLogfmt:
+		return st.exportLogfmt(ctx, query, w, options)
+	}
+
+	return errors.New("export: unknown format")
+}
+
+// exportNDJSON writes one LogRecord per line
+func (st *storeImplementation) exportNDJSON(ctx context.Context, query LogQueryInterface, w io.Writer, options ExportOptions) error {
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+
+	err := st.forEachLogBatch(ctx, query, options.BatchSize, func(batch []LogInterface) error {
+		for _, logEntry := range batch {
+			if err := encoder.Encode(NewLogRecord(logEntry)); err != nil {
+				return err
+			}
+		}
+		return buffered.Flush()
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return buffered.Flush()
+}
+
+// exportCSV writes the fixed columns, the context columns and the context
+func (st *storeImplementation) exportCSV(ctx context.Context, query LogQueryInterface, w io.Writer, options ExportOptions) error {
+	writer := csv.NewWriter(w)
+
+	header := slices.Concat(exportCSVColumns, options.ContextColumns, []string{COLUMN_CONTEXT})
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	err := st.forEachLogBatch(ctx, query, options.BatchSize, func(batch []LogInterface) error {
+		for _, logEntry := range batch {
+			row := []string{
+				logEntry.GetID(),
+				formatExportTime(logEntry.GetTime()),
+				logEntry.GetLevel(),
+				logEntry.GetMessage(),
+				logEntry.GetTraceID(),
+				logEntry.GetSpanID(),
+				logEntry.GetRequestID(),
+				logEntry.GetServiceName(),
+				logEntry.GetHostName(),
+				logEntry.GetEnvironment(),
+				logEntry.GetTenantID(),
+			}
+
+			if len(options.ContextColumns) > 0 {
+				fields := flattenContext(logEntry.GetContext())
+				for _, column := range options.ContextColumns {
+					row = append(row, fields[column])
+				}
+			}
+
+			row = append(row, logEntry.GetContext())
+
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+
+		writer.Flush()
+		return writer.Error()
+	})
+
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportLogfmt writes the time, level, message and non-empty metadata,
+// followed by the flattened context keys in sorted order
+func (st *storeImplementation) exportLogfmt(ctx context.Context, query LogQueryInterface, w io.Writer, options ExportOptions) error {
+	buffered := bufio.NewWriter(w)
+
+	err := st.forEachLogBatch(ctx, query, options.BatchSize, func(batch []LogInterface) error {
+		for _, logEntry := range batch {
+			line := &strings.Builder{}
+
+			writeLogfmtPair(line, COLUMN_TIME, formatExportTime(logEntry.GetTime()))
+			writeLogfmtPair(line, COLUMN_LEVEL, logEntry.GetLevel())
+			writeLogfmtPair(line, "msg", logEntry.GetMessage())
+			writeLogfmtPair(line, COLUMN_ID, logEntry.GetID())
+
+			metadata := [][2]string{
+				{COLUMN_TRACE_ID, logEntry.GetTraceID()},
+				{COLUMN_SPAN_ID, logEntry.GetSpanID()},
+				{COLUMN_REQUEST_ID, logEntry.GetRequestID()},
+				{COLUMN_SERVICE_NAME, logEntry.GetServiceName()},
+				{COLUMN_HOST_NAME, logEntry.GetHostName()},
+				{COLUMN_ENVIRONMENT, logEntry.GetEnvironment()},
+				{COLUMN_TENANT_ID, logEntry.GetTenantID()},
+			}
+
+			for _, pair := range metadata {
+				if pair[1] != "" {
+					writeLogfmtPair(line, pair[0], pair[1])
+				}
+			}
+
+			fields := flattenContext(logEntry.GetContext())
+			keys := make([]string, 0, len(fields))
+			for key := range fields {
+				keys = append(keys, key)
+			}
+			slices.Sort(keys)
+
+			for _, key := range keys {
+				name := logfmtKey(key)
+				if slices.Contains(logfmtFixedKeys, name) {
+					name = COLUMN_CONTEXT + "." + name
+				}
+				writeLogfmtPair(line, name, fields[key])
+			}
+
+			line.WriteString("\n")
+
+			if _, err := buffered.WriteString(line.String()); err != nil {
+				return err
+			}
+		}
+		return buffered.Flush()
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return buffered.Flush()
+}
+
+// logfmtFixedKeys are the keys written for every log, ahead of the context
+var logfmtFixedKeys = []string{
+	COLUMN_TIME,
+	COLUMN_LEVEL,
+	"msg",
+	COLUMN_ID,
+	COLUMN_TRACE_ID,
+	COLUMN_SPAN_ID,
+	COLUMN_REQUEST_ID,
+	COLUMN_SERVICE_NAME,
+	COLUMN_HOST_NAME,
+	COLUMN_ENVIRONMENT,
+	COLUMN_TENANT_ID,
+}
+
+// logfmtKey replaces the characters a logfmt key cannot hold, spaces, "="
+// and quotes, with underscores
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == 0x7f || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// writeLogfmtPair appends key=value to the line, quoting the value when needed
+func writeLogfmtPair(line *strings.Builder, key string, value string) {
+	if line.Len() > 0 {
+		line.WriteString(" ")
+	}
+
+	line.WriteString(key)
+	line.WriteString("=")
+
+	if value == "" || strings.ContainsAny(value, " =\"\t\r\n\\") {
+		line.WriteString(strconv.Quote(value))
+		return
+	}
+
+	line.WriteString(value)
+}
+
+// formatExportTime formats the time of an exported log
+func formatExportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// flattenContext flattens a JSON object context into dotted keys, i.e.
+// {"http":{"status":200}} becomes "http.status" = "200". Strings are kept
+// as they are, other values are JSON encoded. A context which is not a
+// JSON object is returned under the "context" key.
+func flattenContext(context string) map[string]string {
+	fields := map[string]string{}
+
+	if context == "" {
+		return fields
+	}
+
+	// numbers are kept as written, as float64 would round large integers
+	decoder := json.NewDecoder(strings.NewReader(context))
+	decoder.UseNumber()
+
+	object := map[string]any{}
+	if err := decoder.Decode(&object); err != nil || decoder.More() {
+		fields[COLUMN_CONTEXT] = context
+		return fields
+	}
+
+	flattenValue(fields, "", object)
+
+	return fields
+}
+
+// flattenValue adds the value to the fields under the key, recursing into objects
+func flattenValue(fields map[string]string, key string, value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for childKey, childValue := range v {
+			if key != "" {
+				childKey = key + "." + childKey
+			}
+			flattenValue(fields, childKey, childValue)
+		}
+	case string:
+		fields[key] = v
+	default:
+		encoded, _ := json.Marshal(v)
+		fields[key] = string(encoded)
+	}
+}
@@ -0,0 +1,171 @@
+package logstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Store_Export(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_export",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+	at := time.Date(2026, time.October, 19, 14, 0, 0, 0, time.UTC)
+
+	entries := []LogInterface{
+		NewLog().SetLevel(LEVEL_INFO).SetMessage("first call").SetTime(at).
+			SetContext(`{"user_id":"u-1","http":{"status":200}}`),
+		NewLog().SetLevel(LEVEL_ERROR).SetMessage("second").SetTime(at.Add(time.Minute)).
+			SetContext("plain text"),
+		NewLog().SetLevel(LEVEL_INFO).SetMessage("outside").SetTime(at.Add(2 * time.Hour)),
+	}
+
+	for _, entry := range entries {
+		if err := s.LogCreate(ctx, entry); err != nil {
+			t.Fatalf("unexpected error from LogCreate: %v", err)
+		}
+	}
+
+	query := func() LogQueryInterface {
+		return LogQuery().SetTimeGte("2026-10-19 14:00:00").SetTimeLte("2026-10-19 15:00:00")
+	}
+
+	t.Run("ndjson", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		if err := s.Export(ctx, query(), ExportFormatNDJSON, buffer, ExportOptions{BatchSize: 1}); err != nil {
+			t.Fatalf("unexpected error from Export: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 lines, got %d", len(lines))
+		}
+
+		record := LogRecord{}
+		if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+			t.Fatalf("unexpected error decoding line: %v", err)
+		}
+
+		if record.Message != "first call" || !record.Time.Equal(at) {
+			t.Fatalf("unexpected first record: %+v", record)
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		err := s.Export(ctx, query(), ExportFormatCSV, buffer, ExportOptions{
+			ContextColumns: []string{"user_id", "http.status"},
+		})
+
+		if err != nil {
+			t.Fatalf("unexpected error from Export: %v", err)
+		}
+
+		rows, err := csv.NewReader(buffer).ReadAll()
+		if err != nil {
+			t.Fatalf("unexpected error reading CSV: %v", err)
+		}
+
+		if len(rows) != 3 {
+			t.Fatalf("expected a header and 2 rows, got %d", len(rows))
+		}
+
+		header := rows[0]
+		if header[len(header)-3] != "user_id" || header[len(header)-2] != "http.status" || header[len(header)-1] != COLUMN_CONTEXT {
+			t.Fatalf("unexpected header: %v", header)
+		}
+
+		first := rows[1]
+		if first[len(first)-3] != "u-1" || first[len(first)-2] != "200" {
+			t.Fatalf("expected the context columns to be flattened, got %v", first)
+		}
+
+		if rows[2][len(header)-1] != "plain text" {
+			t.Fatalf("expected the raw context to be kept, got %v", rows[2])
+		}
+	})
+
+	t.Run("logfmt", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		if err := s.Export(ctx, query(), ExportFormatLogfmt, buffer); err != nil {
+			t.Fatalf("unexpected error from Export: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 lines, got %d", len(lines))
+		}
+
+		if !strings.HasPrefix(lines[0], `time=2026-10-19T14:00:00Z level=info msg="first call"`) {
+			t.Fatalf("unexpected first line: %s", lines[0])
+		}
+
+		if !strings.HasSuffix(lines[0], "http.status=200 user_id=u-1") {
+			t.Fatalf("expected the sorted context keys at the end, got %s", lines[0])
+		}
+
+		if !strings.HasSuffix(lines[1], `context="plain text"`) {
+			t.Fatalf("expected a plain context to be quoted, got %s", lines[1])
+		}
+	})
+}
+
+func Test_Store_Export_LogfmtKeys(t *testing.T) {
+	s, err := NewStore(NewStoreOptions{
+		DB:                 InitDB(),
+		LogTableName:       "log_export_logfmt",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+
+	err = s.LogCreate(ctx, NewLog().SetLevel(LEVEL_INFO).SetMessage("keys").
+		SetTime(time.Date(2026, time.October, 19, 14, 0, 0, 0, time.UTC)).
+		SetContext(`{"user id":"u-1","a=b":1,"say \"hi\"":true,"time":"later","msg":"inner","big":9007199254740993}`))
+
+	if err != nil {
+		t.Fatalf("unexpected error from LogCreate: %v", err)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := s.Export(ctx, LogQuery(), ExportFormatLogfmt, buffer); err != nil {
+		t.Fatalf("unexpected error from Export: %v", err)
+	}
+
+	line := strings.TrimSpace(buffer.String())
+
+	for _, pair := range []string{
+		"user_id=u-1",
+		"a_b=1",
+		"say__hi_=true",
+		"context.time=later",
+		"context.msg=inner",
+		"big=9007199254740993",
+	} {
+		if !strings.Contains(line, " "+pair) {
+			t.Fatalf("expected %s in %s", pair, line)
+		}
+	}
+
+	if !strings.HasPrefix(line, "time=2026-10-19T14:00:00Z level=info msg=keys ") || strings.Count(line, " time=") != 0 {
+		t.Fatalf("expected the fixed keys once, got %s", line)
+	}
+}
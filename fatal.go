@@ -0,0 +1,50 @@
+package logstore
+
+import (
+	"context"
+	"os"
+)
+
+// FatalBehavior defines what Fatal and FatalWithContext do after logging
+type FatalBehavior int
+
+const (
+	// FatalBehaviorExit flushes buffered entries and exits the process
+	// with status 1. This is the default.
+	FatalBehaviorExit FatalBehavior = iota
+
+	// FatalBehaviorReturn returns to the caller, like any other level
+	FatalBehaviorReturn
+
+	// FatalBehaviorHook flushes buffered entries and calls
+	// NewStoreOptions.FatalHook with the fatal entry
+	FatalBehaviorHook
+)
+
+// osExit is the function used to exit the process, replaceable in tests
+var osExit = os.Exit
+
+// fatal writes a fatal entry, then acts on the configured FatalBehavior
+func (st *storeImplementation) fatal(logEntry LogInterface) error {
+	err := st.Log(logEntry)
+
+	switch st.fatalBehavior {
+	case FatalBehaviorReturn:
+		return err
+	case FatalBehaviorHook:
+		st.flushBeforeExit()
+		st.fatalHook(logEntry)
+		return err
+	default:
+		st.flushBeforeExit()
+		osExit(1)
+		return err
+	}
+}
+
+// flushBeforeExit writes any buffered entries before the process goes down
+func (st *storeImplementation) flushBeforeExit() {
+	if err := st.Flush(context.Background()); err != nil {
+		st.logger.Error("flush before exit failed", "error", err)
+	}
+}
@@ -0,0 +1,155 @@
+package logstore
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// replaceOsExit swaps the exit function for the duration of the test and
+// returns a pointer to the recorded exit code (-1 if exit was not called)
+func replaceOsExit(t *testing.T) *int {
+	code := -1
+	original := osExit
+	osExit = func(c int) { code = c }
+	t.Cleanup(func() { osExit = original })
+	return &code
+}
+
+func Test_Store_Fatal_ExitsByDefault(t *testing.T) {
+	code := replaceOsExit(t)
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                  db,
+		LogTableName:        "log_fatal_exit",
+		AutomigrateEnabled:  true,
+		DeduplicationWindow: time.Hour,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	if err := s.Warn("held back"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := s.FatalWithContext("fatal", map[string]any{"key": "value"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if *code != 1 {
+		t.Fatalf("expected exit with status 1, got %d", *code)
+	}
+
+	count, err := s.LogCount(context.Background(), LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogCount: %v", err)
+	}
+
+	if count != 2 {
+		t.Fatalf("expected buffered entries to be flushed before exit, got %d rows", count)
+	}
+}
+
+func Test_Store_Fatal_Return(t *testing.T) {
+	code := replaceOsExit(t)
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_fatal_return",
+		AutomigrateEnabled: true,
+		FatalBehavior:      FatalBehaviorReturn,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	if err := s.Fatal("fatal"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if *code != -1 {
+		t.Fatalf("expected no exit, got status %d", *code)
+	}
+}
+
+func Test_Store_Fatal_Hook(t *testing.T) {
+	code := replaceOsExit(t)
+	db := InitDB()
+
+	var hooked LogInterface
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_fatal_hook",
+		AutomigrateEnabled: true,
+		FatalBehavior:      FatalBehaviorHook,
+		FatalHook: func(logEntry LogInterface) {
+			hooked = logEntry
+		},
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	if err := s.Fatal("fatal"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if *code != -1 {
+		t.Fatalf("expected no exit, got status %d", *code)
+	}
+
+	if hooked == nil || hooked.GetLevel() != LEVEL_FATAL {
+		t.Fatal("expected the hook to be called with the fatal entry")
+	}
+}
+
+func Test_NewStore_Error_FatalHookRequired(t *testing.T) {
+	_, err := NewStore(NewStoreOptions{
+		DB:            InitDB(),
+		LogTableName:  "log_fatal_hook_required",
+		FatalBehavior: FatalBehaviorHook,
+	})
+
+	if err == nil {
+		t.Fatal("expected error for FatalBehaviorHook without FatalHook, got nil")
+	}
+}
+
+func Test_Store_PanicWithContext_Level(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_panic_level",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected PanicWithContext to panic")
+			}
+		}()
+		s.PanicWithContext("panic", map[string]any{"key": "value"})
+	}()
+
+	count, err := s.LogCount(context.Background(), LogQuery().SetLevel(LEVEL_PANIC))
+	if err != nil {
+		t.Fatalf("unexpected error from LogCount: %v", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("expected 1 panic level log, got %d", count)
+	}
+}
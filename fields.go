@@ -0,0 +1,134 @@
+package logstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Field is a typed key/value pair for the variadic loggers (Infow, Errorw, ...)
+type Field struct {
+	Key   string
+	Value any
+}
+
+// String returns a string field
+func String(key string, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int returns an int field
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int64 returns an int64 field
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Float64 returns a float64 field
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Bool returns a bool field
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Duration returns a time.Duration field, encoded in nanoseconds like encoding/json does
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Time returns a time.Time field
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err returns an "error" field holding the error message, or null for a nil error
+func Err(err error) Field {
+	if err == nil {
+		return Field{Key: "error", Value: nil}
+	}
+	return Field{Key: "error", Value: err.Error()}
+}
+
+// Any returns a field with an arbitrary JSON encodable value
+func Any(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// parseFields converts the arguments of the variadic loggers to fields.
+// Arguments are either Field values or alternating string keys and values;
+// an odd number of key/value arguments, or a key which is not a string, is
+// an error.
+func parseFields(args []any) ([]Field, error) {
+	fields := make([]Field, 0, len(args))
+
+	for i := 0; i < len(args); {
+		if field, ok := args[i].(Field); ok {
+			fields = append(fields, field)
+			i++
+			continue
+		}
+
+		key, ok := args[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("log fields: key at position %d is %T, expected string or Field", i, args[i])
+		}
+
+		if i+1 >= len(args) {
+			return nil, errors.New("log fields: odd number of arguments, key " + key + " has no value")
+		}
+
+		fields = append(fields, Field{Key: key, Value: args[i+1]})
+		i += 2
+	}
+
+	return fields, nil
+}
+
+// encodeFields encodes fields as a JSON object, the same as marshalling a
+// map of them would: keys sorted, and the last value winning for a repeated key
+func encodeFields(fields []Field) (string, error) {
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].Key < fields[j].Key
+	})
+
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+
+	written := 0
+	for i, field := range fields {
+		if i+1 < len(fields) && fields[i+1].Key == field.Key {
+			continue
+		}
+
+		key, err := json.Marshal(field.Key)
+		if err != nil {
+			return "", err
+		}
+
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return "", err
+		}
+
+		if written > 0 {
+			buffer.WriteByte(',')
+		}
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+		written++
+	}
+
+	buffer.WriteByte('}')
+
+	return buffer.String(), nil
+}
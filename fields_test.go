@@ -0,0 +1,101 @@
+package logstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_EncodeFields_MatchesMapEncoding(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	fields, err := parseFields([]any{
+		"user", "u-1",
+		Int("attempt", 3),
+		"ms", 1500 * time.Millisecond,
+		Time("at", now),
+		Bool("ok", false),
+		Err(errors.New("boom")),
+		"html", "<b>",
+		"user", "u-2",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error from parseFields: %v", err)
+	}
+
+	got, err := encodeFields(fields)
+	if err != nil {
+		t.Fatalf("unexpected error from encodeFields: %v", err)
+	}
+
+	expected, _ := json.Marshal(map[string]any{
+		"user":    "u-2",
+		"attempt": 3,
+		"ms":      1500 * time.Millisecond,
+		"at":      now,
+		"ok":      false,
+		"error":   "boom",
+		"html":    "<b>",
+	})
+
+	if got != string(expected) {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+func Test_ParseFields_Errors(t *testing.T) {
+	if _, err := parseFields([]any{"user", "u-1", "ms"}); err == nil {
+		t.Fatal("expected error for odd number of arguments, got nil")
+	}
+
+	if _, err := parseFields([]any{42, "value"}); err == nil {
+		t.Fatal("expected error for non-string key, got nil")
+	}
+
+	fields, err := parseFields(nil)
+	if err != nil || len(fields) != 0 {
+		t.Fatalf("expected no fields and no error, got %v %v", fields, err)
+	}
+}
+
+func Test_Store_Infow(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_infow",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	if err := s.Infow("request done", "user", "u-1", Int("status", 200)); err != nil {
+		t.Fatalf("unexpected error from Infow: %v", err)
+	}
+
+	if err := s.Warnw("odd", "user"); err == nil {
+		t.Fatal("expected error from Warnw with odd arguments, got nil")
+	}
+
+	logs, err := s.LogList(context.Background(), LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	if len(logs) != 1 {
+		t.Fatalf("expected only the valid call to be logged, got %d logs", len(logs))
+	}
+
+	if logs[0].GetLevel() != LEVEL_INFO {
+		t.Fatalf("expected level %s, got %s", LEVEL_INFO, logs[0].GetLevel())
+	}
+
+	if logs[0].GetContext() != `{"status":200,"user":"u-1"}` {
+		t.Fatalf("unexpected context %s", logs[0].GetContext())
+	}
+}
@@ -0,0 +1,59 @@
+package logstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+var (
+	fingerprintUUIDRegexp   = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	fingerprintHexRegexp    = regexp.MustCompile(`0[xX][0-9a-fA-F]+|\b[0-9a-fA-F]{8,}\b`)
+	fingerprintNumberRegexp = regexp.MustCompile(`\d+(\.\d+)?`)
+)
+
+// Fingerprint returns the issue grouping key for a message and an optional
+// stack trace. UUIDs, hex values and numbers are stripped from the message,
+// and only function names are taken from the stack, so occurrences of the
+// same problem share a fingerprint.
+func Fingerprint(message string, stack string) string {
+	hash := sha256.New()
+	hash.Write([]byte(NormalizeMessage(message)))
+
+	if stack != "" {
+		hash.Write([]byte{0})
+		hash.Write([]byte(normalizeStack(stack)))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// NormalizeMessage replaces the variable parts of a message (UUIDs, hex
+// values and numbers) with placeholders
+func NormalizeMessage(message string) string {
+	message = fingerprintUUIDRegexp.ReplaceAllString(message, "<uuid>")
+	message = fingerprintHexRegexp.ReplaceAllString(message, "<hex>")
+	message = fingerprintNumberRegexp.ReplaceAllString(message, "<num>")
+	return strings.TrimSpace(message)
+}
+
+// normalizeStack keeps only the function names of a stack trace, dropping
+// file paths, line numbers and offsets which vary between builds
+func normalizeStack(stack string) string {
+	functions := []string{}
+
+	for _, line := range strings.Split(stack, "\n") {
+		if line == "" || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		functions = append(functions, strings.TrimSpace(line))
+	}
+
+	return strings.Join(functions, "\n")
+}
+
+// isIssueLevel returns whether entries of the level are fingerprinted
+func isIssueLevel(level string) bool {
+	return level == LEVEL_ERROR || level == LEVEL_FATAL || level == LEVEL_PANIC
+}
@@ -0,0 +1,45 @@
+package logstore
+
+import "testing"
+
+func Test_NormalizeMessage(t *testing.T) {
+	cases := map[string]string{
+		"db timeout after 30s":                                     "db timeout after <num>s",
+		"user 550e8400-e29b-41d4-a716-446655440000 not found":      "user <uuid> not found",
+		"bad pointer 0xc000123abc":                                 "bad pointer <hex>",
+		"commit 9fceb02d0ae598e95dc970b74767f19372d61af8 rejected": "commit <hex> rejected",
+		"took 1.5 ms": "took <num> ms",
+	}
+
+	for message, expected := range cases {
+		if got := NormalizeMessage(message); got != expected {
+			t.Fatalf("NormalizeMessage(%q): expected %q, got %q", message, expected, got)
+		}
+	}
+}
+
+func Test_Fingerprint(t *testing.T) {
+	a := Fingerprint("order 123 failed", "")
+	b := Fingerprint("order 456 failed", "")
+	c := Fingerprint("payment 123 failed", "")
+
+	if a != b {
+		t.Fatal("expected messages differing only by numbers to share a fingerprint")
+	}
+
+	if a == c {
+		t.Fatal("expected different messages to have different fingerprints")
+	}
+
+	stack1 := "main.handler\n\t/app/main.go:10\nmain.main\n\t/app/main.go:20\n"
+	stack2 := "main.handler\n\t/build/main.go:12\nmain.main\n\t/build/main.go:25\n"
+	stack3 := "main.other\n\t/app/main.go:10\nmain.main\n\t/app/main.go:20\n"
+
+	if Fingerprint("boom", stack1) != Fingerprint("boom", stack2) {
+		t.Fatal("expected stacks with the same functions to share a fingerprint")
+	}
+
+	if Fingerprint("boom", stack1) == Fingerprint("boom", stack3) {
+		t.Fatal("expected stacks with different functions to have different fingerprints")
+	}
+}
@@ -0,0 +1,433 @@
+// Package grpcstore provides gRPC server and client interceptors adding an
+// entry per call to a log store, with the method, status code, duration,
+// peer and request ID of the call.
+package grpcstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dracory/logstore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// defaultMaxPayloadSize is the default number of payload bytes recorded
+const defaultMaxPayloadSize = 4096
+
+// Options define the options of the interceptors
+type Options struct {
+	// LogPayloads records the request and response messages of unary
+	// calls, as JSON truncated to MaxPayloadSize
+	LogPayloads bool
+
+	// MaxPayloadSize is the number of payload bytes recorded, by default 4096
+	MaxPayloadSize int
+
+	// RequestIDMetadataKey is the metadata key carrying the request ID, by
+	// default "x-request-id". Server interceptors add the ID to the call
+	// context, and client interceptors send the ID of the call context.
+	RequestIDMetadataKey string
+
+	// SkipMethods are the full methods not logged, i.e.
+	// "/grpc.health.v1.Health/Check"
+	SkipMethods []string
+
+	// CodeLevel maps a status code to a log level, by default DefaultCodeLevel
+	CodeLevel func(code codes.Code) string
+
+	// ErrorHandler is called when an entry cannot be added to the store, by
+	// default the error is logged with the default slog logger
+	ErrorHandler func(ctx context.Context, err error)
+}
+
+// DefaultCodeLevel logs OK as info, the codes caused by the client as
+// warnings, and the codes caused by the server or network as errors
+func DefaultCodeLevel(code codes.Code) string {
+	switch code {
+	case codes.OK:
+		return logstore.LEVEL_INFO
+	case codes.Canceled,
+		codes.InvalidArgument,
+		codes.NotFound,
+		codes.AlreadyExists,
+		codes.PermissionDenied,
+		codes.Unauthenticated,
+		codes.ResourceExhausted,
+		codes.FailedPrecondition,
+		codes.Aborted,
+		codes.OutOfRange:
+		return logstore.LEVEL_WARNING
+	}
+
+	return logstore.LEVEL_ERROR
+}
+
+// interceptor holds the store and options shared by the interceptors
+type interceptor struct {
+	store   logstore.StoreInterface
+	options Options
+}
+
+func newInterceptor(store logstore.StoreInterface, opts []Options) *interceptor {
+	i := &interceptor{store: store}
+
+	if len(opts) > 0 {
+		i.options = opts[0]
+	}
+
+	if i.options.MaxPayloadSize <= 0 {
+		i.options.MaxPayloadSize = defaultMaxPayloadSize
+	}
+
+	if i.options.RequestIDMetadataKey == "" {
+		i.options.RequestIDMetadataKey = "x-request-id"
+	}
+
+	if i.options.CodeLevel == nil {
+		i.options.CodeLevel = DefaultCodeLevel
+	}
+
+	if i.options.ErrorHandler == nil {
+		i.options.ErrorHandler = func(ctx context.Context, err error) {
+			slog.ErrorContext(ctx, "grpcstore: adding log entry failed", "error", err)
+		}
+	}
+
+	return i
+}
+
+// == SERVER ==================================================================
+
+// UnaryServerInterceptor returns an interceptor adding an entry per unary call
+func UnaryServerInterceptor(store logstore.StoreInterface, opts ...Options) grpc.UnaryServerInterceptor {
+	i := newInterceptor(store, opts)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if i.skip(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		start := time.Now()
+		ctx, requestID := i.serverContext(ctx)
+
+		resp, err := handler(ctx, req)
+
+		fields := i.fields("server", "unary", info.FullMethod, start, peerOf(ctx), requestID)
+		if i.options.LogPayloads {
+			fields["request"] = i.payload(req)
+			if err == nil {
+				fields["response"] = i.payload(resp)
+			}
+		}
+
+		i.log(ctx, info.FullMethod, err, requestID, fields)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns an interceptor adding an entry per
+// streaming call, once it ends
+func StreamServerInterceptor(store logstore.StoreInterface, opts ...Options) grpc.StreamServerInterceptor {
+	i := newInterceptor(store, opts)
+
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if i.skip(info.FullMethod) {
+			return handler(srv, stream)
+		}
+
+		start := time.Now()
+		ctx, requestID := i.serverContext(stream.Context())
+
+		wrapped := &serverStream{ServerStream: stream, ctx: ctx}
+		err := handler(srv, wrapped)
+
+		fields := i.fields("server", "stream", info.FullMethod, start, peerOf(ctx), requestID)
+		fields["messages_sent"] = wrapped.sent
+		fields["messages_received"] = wrapped.received
+
+		i.log(ctx, info.FullMethod, err, requestID, fields)
+		return err
+	}
+}
+
+// serverContext adds the request ID of the incoming metadata to the
+// context, and returns the request ID of the call
+func (i *interceptor) serverContext(ctx context.Context) (context.Context, string) {
+	if values := metadata.ValueFromIncomingContext(ctx, i.options.RequestIDMetadataKey); len(values) > 0 && values[0] != "" {
+		return logstore.ContextWithCorrelationIDs(ctx, logstore.CorrelationIDs{RequestID: values[0]}), values[0]
+	}
+
+	return ctx, logstore.CorrelationIDsFromContext(ctx).RequestID
+}
+
+// serverStream counts the messages of a server stream, and carries the
+// context with the request ID
+type serverStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	sent     int
+	received int
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent++
+	}
+	return err
+}
+
+func (s *serverStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received++
+	}
+	return err
+}
+
+// == CLIENT ==================================================================
+
+// UnaryClientInterceptor returns an interceptor adding an entry per unary call
+func UnaryClientInterceptor(store logstore.StoreInterface, opts ...Options) grpc.UnaryClientInterceptor {
+	i := newInterceptor(store, opts)
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		if i.skip(method) {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+
+		start := time.Now()
+		ctx, requestID := i.clientContext(ctx)
+
+		p := &peer.Peer{}
+		err := invoker(ctx, method, req, reply, cc, append(callOpts, grpc.Peer(p))...)
+
+		fields := i.fields("client", "unary", method, start, peerAddress(p), requestID)
+		if i.options.LogPayloads {
+			fields["request"] = i.payload(req)
+			if err == nil {
+				fields["response"] = i.payload(reply)
+			}
+		}
+
+		i.log(ctx, method, err, requestID, fields)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns an interceptor adding an entry per
+// streaming call, once it ends
+func StreamClientInterceptor(store logstore.StoreInterface, opts ...Options) grpc.StreamClientInterceptor {
+	i := newInterceptor(store, opts)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if i.skip(method) {
+			return streamer(ctx, desc, cc, method, callOpts...)
+		}
+
+		start := time.Now()
+		ctx, requestID := i.clientContext(ctx)
+
+		p := &peer.Peer{}
+		stream, err := streamer(ctx, desc, cc, method, append(callOpts, grpc.Peer(p))...)
+		if err != nil {
+			i.log(ctx, method, err, requestID, i.fields("client", "stream", method, start, peerAddress(p), requestID))
+			return nil, err
+		}
+
+		return &clientStream{
+			ClientStream:  stream,
+			interceptor:   i,
+			ctx:           ctx,
+			method:        method,
+			start:         start,
+			peer:          p,
+			requestID:     requestID,
+			serverStreams: desc.ServerStreams,
+		}, nil
+	}
+}
+
+// clientContext sends the request ID of the context in the outgoing
+// metadata, and returns the request ID of the call
+func (i *interceptor) clientContext(ctx context.Context) (context.Context, string) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if values := md.Get(i.options.RequestIDMetadataKey); len(values) > 0 && values[0] != "" {
+		return ctx, values[0]
+	}
+
+	requestID := logstore.CorrelationIDsFromContext(ctx).RequestID
+	if requestID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, i.options.RequestIDMetadataKey, requestID)
+	}
+
+	return ctx, requestID
+}
+
+// clientStream adds the entry of a client stream once it ends, which is
+// when receiving fails or, for streams without server streaming, when the
+// response is received
+type clientStream struct {
+	grpc.ClientStream
+	interceptor   *interceptor
+	ctx           context.Context
+	method        string
+	start         time.Time
+	peer          *peer.Peer
+	requestID     string
+	serverStreams bool
+	sent          int
+	received      int
+	finished      bool
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.sent++
+	}
+	return err
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+
+	switch {
+	case err == nil:
+		s.received++
+		if !s.serverStreams {
+			s.finish(nil)
+		}
+	case errors.Is(err, io.EOF):
+		s.finish(nil)
+	default:
+		s.finish(err)
+	}
+
+	return err
+}
+
+// finish adds the entry of the stream, once
+func (s *clientStream) finish(err error) {
+	if s.finished {
+		return
+	}
+	s.finished = true
+
+	fields := s.interceptor.fields("client", "stream", s.method, s.start, peerAddress(s.peer), s.requestID)
+	fields["messages_sent"] = s.sent
+	fields["messages_received"] = s.received
+
+	s.interceptor.log(s.ctx, s.method, err, s.requestID, fields)
+}
+
+// == HELPERS =================================================================
+
+// skip reports whether the method is not logged
+func (i *interceptor) skip(method string) bool {
+	return slices.Contains(i.options.SkipMethods, method)
+}
+
+// fields returns the context fields shared by every call
+func (i *interceptor) fields(side string, kind string, method string, start time.Time, peerAddress string, requestID string) map[string]any {
+	fields := map[string]any{
+		"side":        side,
+		"kind":        kind,
+		"method":      method,
+		"duration_ms": float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if peerAddress != "" {
+		fields["peer"] = peerAddress
+	}
+
+	if requestID != "" {
+		fields["request_id"] = requestID
+	}
+
+	return fields
+}
+
+// log adds the entry of a call
+func (i *interceptor) log(ctx context.Context, method string, err error, requestID string, fields map[string]any) {
+	code := status.Code(err)
+	fields["code"] = code.String()
+
+	if err != nil {
+		fields["error"] = status.Convert(err).Message()
+	}
+
+	logEntry := logstore.NewLog().
+		SetLevel(i.options.CodeLevel(code)).
+		SetMessage(method + " " + code.String()).
+		SetRequestID(requestID)
+
+	if contextBytes, err := json.Marshal(fields); err == nil {
+		logEntry.SetContext(string(contextBytes))
+	}
+
+	if err := i.store.LogCtx(ctx, logEntry); err != nil {
+		i.options.ErrorHandler(ctx, err)
+	}
+}
+
+// payload encodes a message as JSON, truncated to MaxPayloadSize. A
+// truncated payload is recorded as a string.
+func (i *interceptor) payload(message any) any {
+	var data []byte
+	var err error
+
+	if protoMessage, ok := message.(proto.Message); ok {
+		data, err = protojson.Marshal(protoMessage)
+	} else {
+		data, err = json.Marshal(message)
+	}
+
+	if err != nil {
+		return nil
+	}
+
+	if len(data) <= i.options.MaxPayloadSize {
+		return json.RawMessage(data)
+	}
+
+	data = data[:i.options.MaxPayloadSize]
+	for len(data) > 0 && !utf8.Valid(data) {
+		data = data[:len(data)-1]
+	}
+
+	return string(data) + "..."
+}
+
+// peerOf returns the address of the peer of a server call
+func peerOf(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	return peerAddress(p)
+}
+
+// peerAddress returns the address of the peer, if known
+func peerAddress(p *peer.Peer) string {
+	if p == nil || p.Addr == nil {
+		return ""
+	}
+	return p.Addr.String()
+}
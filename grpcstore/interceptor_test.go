@@ -0,0 +1,226 @@
+package grpcstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dracory/logstore"
+	"github.com/dracory/logstore/internal/storetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// logsBySide lists the logs of the server or client interceptors
+func logsBySide(t *testing.T, store logstore.StoreInterface, side string) []map[string]any {
+	t.Helper()
+
+	logs, err := store.LogList(context.Background(), logstore.LogQuery().
+		SetContextContains(`"side":"`+side+`"`).
+		SetOrderBy(logstore.COLUMN_TIME).
+		SetOrderDirection("asc"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := []map[string]any{}
+	for _, logEntry := range logs {
+		fields := map[string]any{}
+		if err := json.Unmarshal([]byte(logEntry.GetContext()), &fields); err != nil {
+			t.Fatal(err)
+		}
+		fields["_level"] = logEntry.GetLevel()
+		fields["_message"] = logEntry.GetMessage()
+		fields["_request_id"] = logEntry.GetRequestID()
+		entries = append(entries, fields)
+	}
+
+	return entries
+}
+
+// findLog returns the log of the method with the code
+func findLog(t *testing.T, logs []map[string]any, method string, code string) map[string]any {
+	t.Helper()
+
+	for _, fields := range logs {
+		if fields["method"] == method && fields["code"] == code {
+			return fields
+		}
+	}
+
+	t.Fatalf("expected a log for %s %s in %v", method, code, logs)
+	return nil
+}
+
+func Test_Interceptors(t *testing.T) {
+	serverStore := storetest.NewStore(t, logstore.NewStoreOptions{LogTableName: "log_grpc"})
+	clientStore := storetest.NewStore(t, logstore.NewStoreOptions{LogTableName: "log_grpc"})
+
+	listener := bufconn.Listen(1024 * 1024)
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor(serverStore, Options{LogPayloads: true, MaxPayloadSize: 64})),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor(serverStore)),
+	)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("billing", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(UnaryClientInterceptor(clientStore)),
+		grpc.WithChainStreamInterceptor(StreamClientInterceptor(clientStore)),
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	client := healthpb.NewHealthClient(conn)
+	ctx := logstore.ContextWithCorrelationIDs(context.Background(), logstore.CorrelationIDs{RequestID: "req-1"})
+
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "billing"}); err != nil {
+		t.Fatalf("unexpected error from Check: %v", err)
+	}
+
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: strings.Repeat("unknown", 20)})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+
+	watchCtx, cancelWatch := context.WithCancel(ctx)
+	watch, err := client.Watch(watchCtx, &healthpb.HealthCheckRequest{Service: "billing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := watch.Recv(); err != nil {
+		t.Fatalf("unexpected error from Recv: %v", err)
+	}
+
+	cancelWatch()
+
+	if _, err := watch.Recv(); status.Code(err) != codes.Canceled {
+		t.Fatalf("expected Canceled, got %v", err)
+	}
+
+	// the server handler ends asynchronously after the cancellation
+	deadline := time.Now().Add(2 * time.Second)
+	for len(logsBySide(t, serverStore, "server")) < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	serverLogs := logsBySide(t, serverStore, "server")
+	if len(serverLogs) != 3 {
+		t.Fatalf("expected 3 server logs, got %v", serverLogs)
+	}
+
+	check := findLog(t, serverLogs, "/grpc.health.v1.Health/Check", "OK")
+	if check["_level"] != logstore.LEVEL_INFO || check["_request_id"] != "req-1" || check["kind"] != "unary" {
+		t.Fatalf("unexpected Check log: %v", check)
+	}
+
+	if request, _ := check["request"].(map[string]any); request["service"] != "billing" {
+		t.Fatalf("expected the request payload: %v", check)
+	}
+
+	if _, ok := check["duration_ms"].(float64); !ok || check["peer"] == nil {
+		t.Fatalf("expected the duration and peer: %v", check)
+	}
+
+	notFound := findLog(t, serverLogs, "/grpc.health.v1.Health/Check", "NotFound")
+	if notFound["_level"] != logstore.LEVEL_WARNING {
+		t.Fatalf("expected NotFound to be a warning: %v", notFound)
+	}
+
+	if request, ok := notFound["request"].(string); !ok || len(request) > 64+len("...") || !strings.HasSuffix(request, "...") {
+		t.Fatalf("expected the request payload to be truncated: %v", notFound["request"])
+	}
+
+	watchLog := findLog(t, serverLogs, "/grpc.health.v1.Health/Watch", "Canceled")
+	if watchLog["kind"] != "stream" || watchLog["messages_sent"] != float64(1) || watchLog["_request_id"] != "req-1" {
+		t.Fatalf("unexpected Watch log: %v", watchLog)
+	}
+
+	clientLogs := logsBySide(t, clientStore, "client")
+	if len(clientLogs) != 3 {
+		t.Fatalf("expected 3 client logs, got %v", clientLogs)
+	}
+
+	clientCheck := findLog(t, clientLogs, "/grpc.health.v1.Health/Check", "OK")
+	if clientCheck["_request_id"] != "req-1" || clientCheck["request"] != nil {
+		t.Fatalf("expected the request ID, without payloads: %v", clientCheck)
+	}
+
+	clientWatch := findLog(t, clientLogs, "/grpc.health.v1.Health/Watch", "Canceled")
+	if clientWatch["messages_received"] != float64(1) {
+		t.Fatalf("unexpected client Watch log: %v", clientWatch)
+	}
+}
+
+func Test_DefaultCodeLevel(t *testing.T) {
+	levels := map[codes.Code]string{
+		codes.OK:               logstore.LEVEL_INFO,
+		codes.NotFound:         logstore.LEVEL_WARNING,
+		codes.Unauthenticated:  logstore.LEVEL_WARNING,
+		codes.Internal:         logstore.LEVEL_ERROR,
+		codes.Unavailable:      logstore.LEVEL_ERROR,
+		codes.DeadlineExceeded: logstore.LEVEL_ERROR,
+	}
+
+	for code, expected := range levels {
+		if actual := DefaultCodeLevel(code); actual != expected {
+			t.Errorf("expected %s for %s, got %s", expected, code, actual)
+		}
+	}
+}
+
+// failingStore is a store failing to add entries
+type failingStore struct {
+	logstore.StoreInterface
+}
+
+func (failingStore) LogCtx(ctx context.Context, logEntry logstore.LogInterface) error {
+	return errors.New("store unavailable")
+}
+
+func Test_Interceptors_ErrorHandler(t *testing.T) {
+	var handled error
+
+	interceptor := UnaryServerInterceptor(failingStore{}, Options{
+		ErrorHandler: func(ctx context.Context, err error) {
+			handled = err
+		},
+	})
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Call"}
+	resp, err := interceptor(context.Background(), "request", info, func(ctx context.Context, req any) (any, error) {
+		return "response", nil
+	})
+
+	if err != nil || resp != "response" {
+		t.Fatalf("expected the call to succeed, got %v, %v", resp, err)
+	}
+
+	if handled == nil || handled.Error() != "store unavailable" {
+		t.Fatalf("expected the store error to be handled, got %v", handled)
+	}
+}
@@ -0,0 +1,351 @@
+package logstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// defaultHTTPLimit is the number of logs listed when no limit is given
+const defaultHTTPLimit = 100
+
+// defaultHTTPMaxLimit is the default maximum number of logs listed per request
+const defaultHTTPMaxLimit = 1000
+
+// httpDeleteBatchSize is the number of logs deleted per query by DELETE /logs
+const httpDeleteBatchSize = 500
+
+// HTTPHandlerOptions define the options of NewHTTPHandler
+type HTTPHandlerOptions struct {
+	// Authorize is called before every request, which is refused with
+	// 403 Forbidden when it returns false. By default every read is allowed.
+	Authorize func(r *http.Request) bool
+
+	// AuthorizeDelete is called before every DELETE request, which is
+	// refused with 403 Forbidden when it returns false. Deleting is
+	// disabled when it is nil.
+	AuthorizeDelete func(r *http.Request) bool
+
+	// MaxLimit is the maximum number of logs listed per request, by default 1000
+	MaxLimit int
+}
+
+// HTTPLogList is the response of GET /logs
+type HTTPLogList struct {
+	Logs   []LogRecord `json:"logs"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+}
+
+// httpHandler serves the JSON API of NewHTTPHandler
+type httpHandler struct {
+	store   StoreInterface
+	options HTTPHandlerOptions
+	mux     *http.ServeMux
+}
+
+// NewHTTPHandler returns a handler serving the logs of the store as JSON:
+//
+//	GET    /logs        lists the logs, filtered by the query parameters
+//	GET    /logs/count  counts the logs, filtered by the query parameters
+//	GET    /logs/stats  summarises the logs, filtered by the query parameters
+//	GET    /logs/{id}   finds a log by ID
+//	DELETE /logs/{id}   deletes a log by ID
+//	DELETE /logs        deletes the logs matching the query parameters
+//
+// The query parameters are named after the LogQueryInterface filters, i.e.
+// level, level_in, message_contains, time_gte, trace_id, limit, offset,
+// order_by and order_direction. Lists are comma separated.
+//
+// Mount it under a prefix with http.StripPrefix, and scope it to a tenant
+// by passing a ForTenant view.
+func NewHTTPHandler(store StoreInterface, opts ...HTTPHandlerOptions) http.Handler {
+	h := &httpHandler{store: store, mux: http.NewServeMux()}
+
+	if len(opts) > 0 {
+		h.options = opts[0]
+	}
+
+	if h.options.MaxLimit <= 0 {
+		h.options.MaxLimit = defaultHTTPMaxLimit
+	}
+
+	h.mux.HandleFunc("GET /logs", h.list)
+	h.mux.HandleFunc("GET /logs/count", h.count)
+	h.mux.HandleFunc("GET /logs/stats", h.stats)
+	h.mux.HandleFunc("GET /logs/{id}", h.find)
+	h.mux.HandleFunc("DELETE /logs/{id}", h.deleteByID)
+	h.mux.HandleFunc("DELETE /logs", h.delete)
+
+	return h
+}
+
+func (h *httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.options.Authorize != nil && !h.options.Authorize(r) {
+		writeHTTPError(w, http.StatusForbidden, errors.New("forbidden"))
+		return
+	}
+
+	if r.Method == http.MethodDelete && (h.options.AuthorizeDelete == nil || !h.options.AuthorizeDelete(r)) {
+		writeHTTPError(w, http.StatusForbidden, errors.New("deleting logs is not allowed"))
+		return
+	}
+
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *httpHandler) list(w http.ResponseWriter, r *http.Request) {
+	query, err := logQueryFromValues(r.URL.Query())
+	if err != nil {
+		writeHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if !query.IsLimitSet() {
+		query.SetLimit(defaultHTTPLimit)
+	}
+
+	if query.GetLimit() > h.options.MaxLimit {
+		query.SetLimit(h.options.MaxLimit)
+	}
+
+	if !query.IsOrderBySet() {
+		query.SetOrderBy(COLUMN_TIME).SetOrderDirection("desc")
+	}
+
+	logs, err := h.store.LogList(r.Context(), query)
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	records := make([]LogRecord, 0, len(logs))
+	for _, logEntry := range logs {
+		records = append(records, NewLogRecord(logEntry))
+	}
+
+	writeHTTPJSON(w, http.StatusOK, HTTPLogList{
+		Logs:   records,
+		Limit:  query.GetLimit(),
+		Offset: query.GetOffset(),
+	})
+}
+
+func (h *httpHandler) count(w http.ResponseWriter, r *http.Request) {
+	query, err := logQueryFromValues(r.URL.Query())
+	if err != nil {
+		writeHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	count, err := h.store.LogCount(r.Context(), query)
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeHTTPJSON(w, http.StatusOK, map[string]int64{"count": count})
+}
+
+func (h *httpHandler) stats(w http.ResponseWriter, r *http.Request) {
+	query, err := logQueryFromValues(r.URL.Query())
+	if err != nil {
+		writeHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	stats, err := h.store.LogStats(r.Context(), query)
+
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeHTTPJSON(w, http.StatusOK, stats)
+}
+
+func (h *httpHandler) find(w http.ResponseWriter, r *http.Request) {
+	logEntry, err := h.store.LogFindByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if logEntry == nil {
+		writeHTTPError(w, http.StatusNotFound, errors.New("log not found"))
+		return
+	}
+
+	writeHTTPJSON(w, http.StatusOK, NewLogRecord(logEntry))
+}
+
+func (h *httpHandler) deleteByID(w http.ResponseWriter, r *http.Request) {
+	if err := h.store.LogDeleteByID(r.Context(), r.PathValue("id")); err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// delete deletes the logs matching the filters, in batches. At least one
+// filter is required, so a bare DELETE /logs cannot empty the table.
+func (h *httpHandler) delete(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	query, err := logQueryFromValues(values)
+	if err != nil {
+		writeHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if !hasHTTPFilters(values) {
+		writeHTTPError(w, http.StatusBadRequest, errors.New("at least one filter is required"))
+		return
+	}
+
+	count, err := h.store.LogCount(r.Context(), query)
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var deleted int64
+
+	for deleted < count {
+		query.SetLimit(httpDeleteBatchSize).SetOffset(0)
+
+		logs, err := h.store.LogList(r.Context(), query)
+		if err != nil {
+			writeHTTPError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		if len(logs) == 0 {
+			break
+		}
+
+		ids := make([]string, 0, len(logs))
+		for _, logEntry := range logs {
+			ids = append(ids, logEntry.GetID())
+		}
+
+		if err := h.store.LogDeleteByIDs(r.Context(), ids); err != nil {
+			writeHTTPError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		deleted += int64(len(ids))
+	}
+
+	writeHTTPJSON(w, http.StatusOK, map[string]int64{"deleted": deleted})
+}
+
+// httpFilterParameters maps the query parameters to the query filters
+var httpFilterParameters = map[string]func(query LogQueryInterface, value string){
+	"id":                   func(q LogQueryInterface, v string) { q.SetID(v) },
+	"id_in":                func(q LogQueryInterface, v string) { q.SetIDIn(splitHTTPList(v)) },
+	"level":                func(q LogQueryInterface, v string) { q.SetLevel(v) },
+	"level_in":             func(q LogQueryInterface, v string) { q.SetLevelIn(splitHTTPList(v)) },
+	"message_contains":     func(q LogQueryInterface, v string) { q.SetMessageContains(v) },
+	"message_not_contains": func(q LogQueryInterface, v string) { q.SetMessageNotContains(v) },
+	"context_contains":     func(q LogQueryInterface, v string) { q.SetContextContains(v) },
+	"context_not_contains": func(q LogQueryInterface, v string) { q.SetContextNotContains(v) },
+	"time_gte":             func(q LogQueryInterface, v string) { q.SetTimeGte(v) },
+	"time_lte":             func(q LogQueryInterface, v string) { q.SetTimeLte(v) },
+	"fingerprint":          func(q LogQueryInterface, v string) { q.SetFingerprint(v) },
+	"trace_id":             func(q LogQueryInterface, v string) { q.SetTraceID(v) },
+	"span_id":              func(q LogQueryInterface, v string) { q.SetSpanID(v) },
+	"request_id":           func(q LogQueryInterface, v string) { q.SetRequestID(v) },
+	"service_name":         func(q LogQueryInterface, v string) { q.SetServiceName(v) },
+	"service_version":      func(q LogQueryInterface, v string) { q.SetServiceVersion(v) },
+	"host_name":            func(q LogQueryInterface, v string) { q.SetHostName(v) },
+	"environment":          func(q LogQueryInterface, v string) { q.SetEnvironment(v) },
+	"instance_id":          func(q LogQueryInterface, v string) { q.SetInstanceID(v) },
+	"tenant_id":            func(q LogQueryInterface, v string) { q.SetTenantID(v) },
+}
+
+// logQueryFromValues builds a query from the filter, paging and order
+// parameters, ignoring empty and unknown parameters
+func logQueryFromValues(values url.Values) (LogQueryInterface, error) {
+	query := LogQuery()
+
+	for name, set := range httpFilterParameters {
+		if value := values.Get(name); value != "" {
+			set(query, value)
+		}
+	}
+
+	if value := values.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit %q", value)
+		}
+		query.SetLimit(limit)
+	}
+
+	if value := values.Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid offset %q", value)
+		}
+		query.SetOffset(offset)
+	}
+
+	if value := values.Get("order_by"); value != "" {
+		if !IsOrderColumn(value) {
+			return nil, fmt.Errorf("invalid order_by %q", value)
+		}
+		query.SetOrderBy(value)
+	}
+
+	if value := strings.ToLower(values.Get("order_direction")); value != "" {
+		if value != "asc" && value != "desc" {
+			return nil, fmt.Errorf("invalid order_direction %q", value)
+		}
+		query.SetOrderDirection(value)
+	}
+
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	return query, nil
+}
+
+// hasHTTPFilters reports whether any filter parameter is set
+func hasHTTPFilters(values url.Values) bool {
+	for name := range httpFilterParameters {
+		if values.Get(name) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// splitHTTPList splits a comma separated parameter, dropping empty items
+func splitHTTPList(value string) []string {
+	items := []string{}
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// writeHTTPJSON writes the value as a JSON response
+func writeHTTPJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// writeHTTPError writes the error as a JSON response
+func writeHTTPError(w http.ResponseWriter, status int, err error) {
+	writeHTTPJSON(w, status, map[string]string{"error": err.Error()})
+}
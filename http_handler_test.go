@@ -0,0 +1,174 @@
+package logstore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_HTTPHandler(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_http",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+	at := time.Date(2026, time.October, 19, 14, 0, 0, 0, time.UTC)
+
+	entries := []LogInterface{
+		NewLog().SetLevel(LEVEL_INFO).SetMessage("user signed in").SetTime(at),
+		NewLog().SetLevel(LEVEL_ERROR).SetMessage("payment failed").SetTime(at.Add(time.Minute)).SetTraceID("trace-1"),
+		NewLog().SetLevel(LEVEL_ERROR).SetMessage("payment declined").SetTime(at.Add(2 * time.Minute)),
+	}
+
+	for _, entry := range entries {
+		if err := s.LogCreate(ctx, entry); err != nil {
+			t.Fatalf("unexpected error from LogCreate: %v", err)
+		}
+	}
+
+	handler := NewHTTPHandler(s, HTTPHandlerOptions{
+		AuthorizeDelete: func(r *http.Request) bool {
+			return r.Header.Get("Authorization") == "Bearer admin"
+		},
+	})
+
+	request := func(method string, target string, header ...string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, target, nil)
+		if len(header) > 0 {
+			r.Header.Set("Authorization", header[0])
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	w := request(http.MethodGet, "/logs?level=error&order_by=time&order_direction=asc")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	list := HTTPLogList{}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(list.Logs) != 2 || list.Logs[0].Message != "payment failed" || list.Limit != defaultHTTPLimit {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+
+	w = request(http.MethodGet, "/logs?trace_id=trace-1&limit=5")
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(list.Logs) != 1 || list.Logs[0].TraceID != "trace-1" || list.Limit != 5 {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+
+	if w := request(http.MethodGet, "/logs?order_by=password"); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected an invalid order_by to be refused, got %d", w.Code)
+	}
+
+	if w := request(http.MethodGet, "/logs?limit=many"); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected an invalid limit to be refused, got %d", w.Code)
+	}
+
+	w = request(http.MethodGet, "/logs/count?level_in=info,error&message_contains=payment")
+	if w.Code != http.StatusOK || w.Body.String() != "{\"count\":2}\n" {
+		t.Fatalf("unexpected count: %d %s", w.Code, w.Body.String())
+	}
+
+	w = request(http.MethodGet, "/logs/stats")
+	stats := Stats{}
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Count != 3 || stats.CountByLevel[LEVEL_ERROR] != 2 || !stats.Oldest.Equal(at) {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	w = request(http.MethodGet, "/logs/stats?level=info")
+	stats = Stats{}
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Count != 1 || len(stats.CountByLevel) != 1 || stats.CountByLevel[LEVEL_INFO] != 1 {
+		t.Fatalf("expected the level filter to apply to the stats: %+v", stats)
+	}
+
+	w = request(http.MethodGet, "/logs/"+entries[0].GetID())
+	record := LogRecord{}
+	if err := json.Unmarshal(w.Body.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Code != http.StatusOK || record.Message != "user signed in" {
+		t.Fatalf("unexpected log: %d %+v", w.Code, record)
+	}
+
+	if w := request(http.MethodGet, "/logs/missing"); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+
+	if w := request(http.MethodDelete, "/logs/"+entries[0].GetID()); w.Code != http.StatusForbidden {
+		t.Fatalf("expected an unauthorized delete to be refused, got %d", w.Code)
+	}
+
+	if w := request(http.MethodDelete, "/logs/"+entries[0].GetID(), "Bearer admin"); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+
+	if w := request(http.MethodDelete, "/logs", "Bearer admin"); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected a delete without filters to be refused, got %d", w.Code)
+	}
+
+	w = request(http.MethodDelete, "/logs?level=error", "Bearer admin")
+	if w.Code != http.StatusOK || w.Body.String() != "{\"deleted\":2}\n" {
+		t.Fatalf("unexpected delete: %d %s", w.Code, w.Body.String())
+	}
+
+	count, err := s.LogCount(ctx, LogQuery())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 0 {
+		t.Fatalf("expected every log to be deleted, got %d", count)
+	}
+}
+
+func Test_HTTPHandler_Authorize(t *testing.T) {
+	s, err := NewStore(NewStoreOptions{
+		DB:                 InitDB(),
+		LogTableName:       "log_http_authorize",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	handler := NewHTTPHandler(s, HTTPHandlerOptions{
+		Authorize: func(r *http.Request) bool { return false },
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/logs", nil))
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", w.Code)
+	}
+}
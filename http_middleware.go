@@ -0,0 +1,217 @@
+package logstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// redactedValue replaces the values of redacted headers and query parameters
+const redactedValue = "[REDACTED]"
+
+// defaultRedactHeaders are the headers redacted when RedactHeaders is nil
+var defaultRedactHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Proxy-Authorization",
+	"Set-Cookie",
+	"X-Api-Key",
+}
+
+// HTTPMiddlewareOptions define the options of HTTPMiddleware
+type HTTPMiddlewareOptions struct {
+	// SkipPaths are the request paths not logged, i.e. "/health"
+	SkipPaths []string
+
+	// LogHeaders records the request headers
+	LogHeaders bool
+
+	// RedactHeaders are the headers whose values are replaced by
+	// "[REDACTED]", by default Authorization, Cookie, Proxy-Authorization,
+	// Set-Cookie and X-Api-Key
+	RedactHeaders []string
+
+	// RedactQueryParams are the query parameters whose values are replaced
+	// by "[REDACTED]", i.e. "token"
+	RedactQueryParams []string
+
+	// RequestIDHeader is the header carrying the request ID, by default
+	// X-Request-ID. The ID is also added to the request context, so the
+	// entries logged by the handler with LogCtx carry it too.
+	RequestIDHeader string
+
+	// TrustForwardedFor takes the remote IP from the X-Forwarded-For
+	// header, to be enabled only behind a proxy setting it
+	TrustForwardedFor bool
+}
+
+// HTTPMiddleware returns a middleware adding one entry per request, with
+// the method, path, query, status, bytes written, duration, remote IP,
+// user agent and request ID as the context. Responses with a 5xx status are
+// logged as errors, 4xx as warnings, and the others as info.
+func HTTPMiddleware(store StoreInterface, opts ...HTTPMiddlewareOptions) func(next http.Handler) http.Handler {
+	options := HTTPMiddlewareOptions{}
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	if options.RedactHeaders == nil {
+		options.RedactHeaders = defaultRedactHeaders
+	}
+
+	if options.RequestIDHeader == "" {
+		options.RequestIDHeader = "X-Request-ID"
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(options.SkipPaths, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+
+			requestID := r.Header.Get(options.RequestIDHeader)
+			if requestID != "" {
+				r = r.WithContext(ContextWithCorrelationIDs(r.Context(), CorrelationIDs{RequestID: requestID}))
+			} else {
+				requestID = CorrelationIDsFromContext(r.Context()).RequestID
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			fields := map[string]any{
+				"method":      r.Method,
+				"path":        r.URL.Path,
+				"status":      recorder.status,
+				"bytes":       recorder.bytes,
+				"duration_ms": float64(time.Since(start).Microseconds()) / 1000,
+				"remote_ip":   remoteIP(r, options.TrustForwardedFor),
+				"user_agent":  r.UserAgent(),
+			}
+
+			if r.URL.RawQuery != "" {
+				fields["query"] = redactQuery(r, options.RedactQueryParams)
+			}
+
+			if requestID != "" {
+				fields["request_id"] = requestID
+			}
+
+			if options.LogHeaders {
+				fields["headers"] = redactHeaders(r.Header, options.RedactHeaders)
+			}
+
+			contextBytes, err := json.Marshal(fields)
+			if err != nil {
+				return
+			}
+
+			logEntry := NewLog().
+				SetLevel(levelOfStatus(recorder.status)).
+				SetMessage(fmt.Sprintf("%s %s %d", r.Method, r.URL.Path, recorder.status)).
+				SetContext(string(contextBytes)).
+				SetRequestID(requestID)
+
+			store.LogCtx(r.Context(), logEntry)
+		})
+	}
+}
+
+// responseRecorder records the status and size of a response
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (rw *responseRecorder) WriteHeader(status int) {
+	if !rw.wroteHeader {
+		rw.status = status
+		rw.wroteHeader = true
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *responseRecorder) Write(p []byte) (int, error) {
+	rw.wroteHeader = true
+	n, err := rw.ResponseWriter.Write(p)
+	rw.bytes += int64(n)
+	return n, err
+}
+
+// Flush lets streaming handlers flush through the recorder
+func (rw *responseRecorder) Flush() {
+	http.NewResponseController(rw.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController
+func (rw *responseRecorder) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// levelOfStatus maps a response status to a log level
+func levelOfStatus(status int) string {
+	switch {
+	case status >= 500:
+		return LEVEL_ERROR
+	case status >= 400:
+		return LEVEL_WARNING
+	}
+	return LEVEL_INFO
+}
+
+// remoteIP returns the IP of the client, from the first X-Forwarded-For
+// address when trusted
+func remoteIP(r *http.Request, trustForwardedFor bool) string {
+	if trustForwardedFor {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(first)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// redactQuery returns the query string with the values of the parameters redacted
+func redactQuery(r *http.Request, params []string) string {
+	if len(params) == 0 {
+		return r.URL.RawQuery
+	}
+
+	values := r.URL.Query()
+	for name := range values {
+		if slices.ContainsFunc(params, func(param string) bool { return strings.EqualFold(param, name) }) {
+			for i := range values[name] {
+				values[name][i] = redactedValue
+			}
+		}
+	}
+
+	return values.Encode()
+}
+
+// redactHeaders returns the headers as a map with the values of the headers redacted
+func redactHeaders(header http.Header, redact []string) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		if slices.ContainsFunc(redact, func(h string) bool { return strings.EqualFold(h, name) }) {
+			headers[name] = redactedValue
+			continue
+		}
+		headers[name] = strings.Join(values, ", ")
+	}
+	return headers
+}
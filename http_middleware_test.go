@@ -0,0 +1,124 @@
+package logstore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_HTTPMiddleware(t *testing.T) {
+	s, err := NewStore(NewStoreOptions{
+		DB:                 InitDB(),
+		LogTableName:       "log_http_middleware",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/missing", http.NotFound)
+	mux.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+		// the handler logs with the request ID of the header
+		s.LogCtx(r.Context(), NewLog().SetLevel(LEVEL_DEBUG).SetMessage("listing orders"))
+		w.Write([]byte("orders"))
+	})
+	mux.HandleFunc("/fail", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusBadGateway)
+	})
+
+	handler := HTTPMiddleware(s, HTTPMiddlewareOptions{
+		SkipPaths:         []string{"/health"},
+		LogHeaders:        true,
+		RedactQueryParams: []string{"token"},
+	})(mux)
+
+	request := httptest.NewRequest(http.MethodGet, "/orders?page=2&token=secret", nil)
+	request.RemoteAddr = "203.0.113.7:51234"
+	request.Header.Set("User-Agent", "test-agent")
+	request.Header.Set("Authorization", "Bearer secret")
+	request.Header.Set("X-Request-ID", "req-1")
+
+	for _, r := range []*http.Request{
+		request,
+		httptest.NewRequest(http.MethodGet, "/health", nil),
+		httptest.NewRequest(http.MethodGet, "/missing", nil),
+		httptest.NewRequest(http.MethodPost, "/fail", nil),
+	} {
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	logs, err := s.LogList(context.Background(), LogQuery().SetOrderBy(COLUMN_MESSAGE).SetOrderDirection("asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	levels := map[string]string{}
+	for _, logEntry := range logs {
+		levels[logEntry.GetMessage()] = logEntry.GetLevel()
+	}
+
+	expected := map[string]string{
+		"GET /orders 200":  LEVEL_INFO,
+		"GET /missing 404": LEVEL_WARNING,
+		"POST /fail 502":   LEVEL_ERROR,
+		"listing orders":   LEVEL_DEBUG,
+	}
+
+	if len(levels) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, levels)
+	}
+
+	for message, level := range expected {
+		if levels[message] != level {
+			t.Fatalf("expected %v, got %v", expected, levels)
+		}
+	}
+
+	for _, logEntry := range logs {
+		if logEntry.GetMessage() == "listing orders" && logEntry.GetRequestID() != "req-1" {
+			t.Fatalf("expected the handler log to carry the request ID, got %q", logEntry.GetRequestID())
+		}
+
+		if logEntry.GetMessage() != "GET /orders 200" {
+			continue
+		}
+
+		if logEntry.GetRequestID() != "req-1" {
+			t.Fatalf("expected the request ID, got %q", logEntry.GetRequestID())
+		}
+
+		if strings.Contains(logEntry.GetContext(), "secret") {
+			t.Fatalf("expected the token and authorization to be redacted: %s", logEntry.GetContext())
+		}
+
+		fields := map[string]any{}
+		if err := json.Unmarshal([]byte(logEntry.GetContext()), &fields); err != nil {
+			t.Fatal(err)
+		}
+
+		if fields["method"] != "GET" || fields["path"] != "/orders" || fields["status"] != float64(200) ||
+			fields["bytes"] != float64(6) || fields["remote_ip"] != "203.0.113.7" ||
+			fields["user_agent"] != "test-agent" || fields["request_id"] != "req-1" {
+			t.Fatalf("unexpected context: %v", fields)
+		}
+
+		if fields["query"] != "page=2&token=%5BREDACTED%5D" {
+			t.Fatalf("unexpected query: %v", fields["query"])
+		}
+
+		headers, _ := fields["headers"].(map[string]any)
+		if headers["Authorization"] != redactedValue {
+			t.Fatalf("unexpected headers: %v", headers)
+		}
+
+		if _, ok := fields["duration_ms"].(float64); !ok {
+			t.Fatalf("expected the duration: %v", fields)
+		}
+	}
+}
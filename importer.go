@@ -0,0 +1,423 @@
+package logstore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dromara/carbon/v2"
+)
+
+// ImportFormat defines the format of the lines read by an Importer
+type ImportFormat int
+
+const (
+	// ImportFormatSlogJSON reads lines written by slog.JSONHandler
+	ImportFormatSlogJSON ImportFormat = iota
+
+	// ImportFormatSlogText reads lines written by slog.TextHandler
+	ImportFormatSlogText
+
+	// ImportFormatNDJSON reads JSON objects written by other tools. The
+	// time is taken from "time", "timestamp", "ts" or "@timestamp", the
+	// level from "level", "severity" or "lvl", and the message from "msg"
+	// or "message".
+	ImportFormatNDJSON
+)
+
+// importKeys lists the keys holding the time, level and message of a line
+type importKeys struct {
+	time    []string
+	level   []string
+	message []string
+}
+
+var slogImportKeys = importKeys{
+	time:    []string{slog.TimeKey},
+	level:   []string{slog.LevelKey},
+	message: []string{slog.MessageKey},
+}
+
+var ndjsonImportKeys = importKeys{
+	time:    []string{"time", "timestamp", "ts", "@timestamp"},
+	level:   []string{"level", "severity", "lvl"},
+	message: []string{"msg", "message"},
+}
+
+// ImporterOptions define the options for creating an importer
+type ImporterOptions struct {
+	// Store is the log store to import into, as returned by NewStore
+	Store StoreInterface
+
+	// Format of the lines to import, by default slog JSON
+	Format ImportFormat
+
+	// BatchSize is the number of entries written per insert, each batch in a
+	// transaction of its own, by default 500
+	BatchSize int
+}
+
+// Importer backfills the log store from log files
+type Importer struct {
+	store     *storeImplementation
+	format    ImportFormat
+	batchSize int
+}
+
+// ImportError is the error of a line which could not be imported
+type ImportError struct {
+	// Line is the line number, starting at 1
+	Line int
+
+	// Err is the parse error
+	Err error
+}
+
+func (e ImportError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e ImportError) Unwrap() error {
+	return e.Err
+}
+
+// ImportResult reports what was imported
+type ImportResult struct {
+	// Lines is the number of non-empty lines read
+	Lines int
+
+	// Imported is the number of entries written
+	Imported int64
+
+	// Errors lists the lines which could not be parsed
+	Errors []ImportError
+}
+
+// NewImporter creates a new importer
+func NewImporter(opts ImporterOptions) (*Importer, error) {
+	if opts.Store == nil {
+		return nil, errors.New("importer: Store is required")
+	}
+
+	store, ok := opts.Store.(*storeImplementation)
+	if !ok {
+		return nil, errors.New("importer: Store must be created with NewStore")
+	}
+
+	importer := &Importer{
+		store:     store,
+		format:    opts.Format,
+		batchSize: opts.BatchSize,
+	}
+
+	if importer.batchSize <= 0 {
+		importer.batchSize = defaultBatchSize
+	}
+
+	return importer, nil
+}
+
+// Import reads r line by line and writes the entries in bulk, keeping their
+// original times. Lines which cannot be parsed are reported in the result
+// and skipped. An error is only returned when reading or writing fails; as
+// each batch is written in a transaction, the entries counted as imported
+// are exactly the entries written.
+func (i *Importer) Import(ctx context.Context, r io.Reader) (ImportResult, error) {
+	result := ImportResult{Errors: []ImportError{}}
+	reader := bufio.NewReader(r)
+	batch := make([]LogInterface, 0, i.batchSize)
+	lineNumber := 0
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := i.store.insertLogs(ctx, batch, i.batchSize); err != nil {
+			return err
+		}
+
+		result.Imported += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		line, readErr := reader.ReadBytes('\n')
+
+		if len(line) > 0 {
+			lineNumber++
+		}
+
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			result.Lines++
+
+			logEntry, err := i.ParseLine(line)
+			if err != nil {
+				result.Errors = append(result.Errors, ImportError{Line: lineNumber, Err: err})
+			} else {
+				i.store.applyTenant(logEntry)
+				batch = append(batch, logEntry)
+			}
+
+			if len(batch) >= i.batchSize {
+				if err := flush(); err != nil {
+					return result, err
+				}
+			}
+		}
+
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+
+		if readErr != nil {
+			return result, readErr
+		}
+	}
+
+	return result, flush()
+}
+
+// ParseLine parses a single line into a log entry. The keys other than
+// the time, level and message are kept as the JSON object context.
+func (i *Importer) ParseLine(line []byte) (LogInterface, error) {
+	switch i.format {
+	case ImportFormatSlogText:
+		object, err := parseLogfmt(string(line))
+		if err != nil {
+			return nil, err
+		}
+		return newImportedLog(object, slogImportKeys)
+	case ImportFormatNDJSON:
+		object, err := parseJSONObject(line)
+		if err != nil {
+			return nil, err
+		}
+		return newImportedLog(object, ndjsonImportKeys)
+	default:
+		object, err := parseJSONObject(line)
+		if err != nil {
+			return nil, err
+		}
+		return newImportedLog(object, slogImportKeys)
+	}
+}
+
+// newImportedLog takes the time, level and message out of the object, and
+// keeps the remaining keys as the context
+func newImportedLog(object map[string]any, keys importKeys) (LogInterface, error) {
+	timeValue, ok := takeKey(object, keys.time)
+	if !ok {
+		return nil, errors.New("missing time")
+	}
+
+	t, err := parseImportTime(timeValue)
+	if err != nil {
+		return nil, err
+	}
+
+	level := LEVEL_INFO
+	if levelValue, ok := takeKey(object, keys.level); ok {
+		level, err = parseImportLevel(levelValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	message := ""
+	if messageValue, ok := takeKey(object, keys.message); ok {
+		message = fmt.Sprint(messageValue)
+	}
+
+	context := ""
+	if len(object) > 0 {
+		encoded, err := json.Marshal(object)
+		if err != nil {
+			return nil, err
+		}
+		context = string(encoded)
+	}
+
+	return NewLog().
+		SetTime(t).
+		SetLevel(level).
+		SetMessage(message).
+		SetContext(context), nil
+}
+
+// takeKey removes and returns the value of the first key present
+func takeKey(object map[string]any, keys []string) (any, bool) {
+	for _, key := range keys {
+		if value, ok := object[key]; ok {
+			delete(object, key)
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// parseJSONObject parses a line holding a JSON object, keeping numbers as
+// they were written
+func parseJSONObject(line []byte) (map[string]any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+
+	object := map[string]any{}
+	if err := decoder.Decode(&object); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	return object, nil
+}
+
+// parseLogfmt parses space separated key=value pairs, with values quoted
+// when they contain spaces, as written by slog.TextHandler
+func parseLogfmt(line string) (map[string]any, error) {
+	object := map[string]any{}
+
+	for line = strings.TrimSpace(line); line != ""; line = strings.TrimLeft(line, " \t") {
+		end := strings.IndexAny(line, "= \t")
+		if end <= 0 || line[end] != '=' {
+			return nil, fmt.Errorf("invalid logfmt pair at %q", line)
+		}
+
+		key := line[:end]
+		line = line[end+1:]
+
+		if strings.HasPrefix(line, `"`) {
+			closing := closingQuote(line)
+			if closing < 0 {
+				return nil, fmt.Errorf("unterminated quoted value for %q", key)
+			}
+
+			value, err := strconv.Unquote(line[:closing+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted value for %q: %w", key, err)
+			}
+
+			object[key] = value
+			line = line[closing+1:]
+			continue
+		}
+
+		end = strings.IndexAny(line, " \t")
+		if end < 0 {
+			end = len(line)
+		}
+
+		object[key] = line[:end]
+		line = line[end:]
+	}
+
+	return object, nil
+}
+
+// closingQuote returns the index of the quote closing the quoted value at
+// the start of s, or -1
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// parseImportTime parses a time string, or a Unix time in seconds,
+// milliseconds, microseconds or nanoseconds
+func parseImportTime(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t.UTC(), nil
+		}
+
+		c := carbon.Parse(v, carbon.UTC)
+		if c.HasError() || c.IsZero() {
+			return time.Time{}, fmt.Errorf("invalid time %q", v)
+		}
+
+		return c.StdTime().UTC(), nil
+	case json.Number:
+		number, err := v.Float64()
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %q", v)
+		}
+
+		switch {
+		case number > 1e17:
+			return time.Unix(0, int64(number)).UTC(), nil
+		case number > 1e14:
+			return time.UnixMicro(int64(number)).UTC(), nil
+		case number > 1e11:
+			return time.UnixMilli(int64(number)).UTC(), nil
+		}
+
+		seconds, fraction := math.Modf(number)
+		return time.Unix(int64(seconds), int64(fraction*1e9)).UTC(), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %v", value)
+}
+
+// parseImportLevel maps slog levels ("INFO", "DEBUG-4"), common level names
+// and numeric levels (10 trace to 60 fatal) to the store's levels
+func parseImportLevel(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(v)); err == nil {
+			return slogLevelToLevel(level), nil
+		}
+
+		switch strings.ToLower(v) {
+		case "trace":
+			return LEVEL_TRACE, nil
+		case "warning":
+			return LEVEL_WARNING, nil
+		case "err":
+			return LEVEL_ERROR, nil
+		case "fatal", "critical", "crit":
+			return LEVEL_FATAL, nil
+		case "panic", "emergency", "emerg":
+			return LEVEL_PANIC, nil
+		}
+	case json.Number:
+		number, err := v.Int64()
+		if err == nil {
+			switch {
+			case number < 20:
+				return LEVEL_TRACE, nil
+			case number < 30:
+				return LEVEL_DEBUG, nil
+			case number < 40:
+				return LEVEL_INFO, nil
+			case number < 50:
+				return LEVEL_WARNING, nil
+			case number < 60:
+				return LEVEL_ERROR, nil
+			default:
+				return LEVEL_FATAL, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("unknown level %v", value)
+}
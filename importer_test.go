@@ -0,0 +1,235 @@
+package logstore
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Importer_Import(t *testing.T) {
+	at := time.Date(2026, time.October, 19, 14, 30, 0, 0, time.UTC)
+
+	record := slog.NewRecord(at, slog.LevelWarn, "disk almost full", 0)
+	record.AddAttrs(slog.String("disk", "/dev/sda"), slog.Group("usage", slog.Int("percent", 93)))
+
+	slogJSON := &bytes.Buffer{}
+	if err := slog.NewJSONHandler(slogJSON, nil).Handle(context.Background(), record); err != nil {
+		t.Fatal(err)
+	}
+
+	slogText := &bytes.Buffer{}
+	if err := slog.NewTextHandler(slogText, nil).Handle(context.Background(), record); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		format ImportFormat
+		input  string
+	}{
+		{"slog json", ImportFormatSlogJSON, slogJSON.String() + "not json\n"},
+		{"slog text", ImportFormatSlogText, slogText.String() + "broken \"line\n"},
+		{"ndjson", ImportFormatNDJSON,
+			`{"ts":1792420200,"severity":"warning","message":"disk almost full","disk":"/dev/sda","usage":{"percent":93}}` + "\n" +
+				`{"message":"no time"}` + "\n"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db := InitDB()
+
+			s, err := NewStore(NewStoreOptions{
+				DB:                 db,
+				LogTableName:       "log_import",
+				AutomigrateEnabled: true,
+			})
+
+			if err != nil {
+				t.Fatal("Store could not be created: " + err.Error())
+			}
+
+			importer, err := NewImporter(ImporterOptions{Store: s, Format: c.format})
+			if err != nil {
+				t.Fatalf("unexpected error from NewImporter: %v", err)
+			}
+
+			ctx := context.Background()
+
+			result, err := importer.Import(ctx, strings.NewReader("\n"+c.input))
+			if err != nil {
+				t.Fatalf("unexpected error from Import: %v", err)
+			}
+
+			if result.Lines != 2 || result.Imported != 1 {
+				t.Fatalf("expected 1 of 2 lines to be imported, got %+v", result)
+			}
+
+			if len(result.Errors) != 1 || result.Errors[0].Line != 3 {
+				t.Fatalf("expected an error for line 3, got %v", result.Errors)
+			}
+
+			logs, err := s.LogList(ctx, LogQuery())
+			if err != nil {
+				t.Fatalf("unexpected error from LogList: %v", err)
+			}
+
+			if len(logs) != 1 {
+				t.Fatalf("expected 1 log, got %d", len(logs))
+			}
+
+			if !logs[0].GetTime().Equal(at) {
+				t.Fatalf("expected the original time %v, got %v", at, logs[0].GetTime())
+			}
+
+			if logs[0].GetLevel() != LEVEL_WARNING || logs[0].GetMessage() != "disk almost full" {
+				t.Fatalf("unexpected level or message: %s %s", logs[0].GetLevel(), logs[0].GetMessage())
+			}
+
+			if !strings.Contains(logs[0].GetContext(), `"disk":"/dev/sda"`) || !strings.Contains(logs[0].GetContext(), "93") {
+				t.Fatalf("expected the remaining keys in the context, got %s", logs[0].GetContext())
+			}
+		})
+	}
+}
+
+func Test_ParseLogfmt(t *testing.T) {
+	object, err := parseLogfmt(`time=2026-10-19T14:30:00Z level=INFO msg="hello \"world\"" user.id=42 empty=`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if object["msg"] != `hello "world"` || object["user.id"] != "42" || object["empty"] != "" {
+		t.Fatalf("unexpected pairs: %v", object)
+	}
+
+	if _, err := parseLogfmt("no pairs here"); err == nil {
+		t.Fatal("expected an error for a line without pairs")
+	}
+}
+
+func Test_Importer_BatchSize(t *testing.T) {
+	db := InitDB()
+	db.SetMaxOpenConns(1)
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_import_batch",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	_, err = db.Exec(`CREATE TRIGGER reject_boom BEFORE INSERT ON log_import_batch
+		WHEN NEW.message = 'boom' BEGIN SELECT RAISE(ABORT, 'rejected'); END`)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the rejected line is in the second insert of 500 rows, unless the
+	// batch of 600 is written at once
+	input := &strings.Builder{}
+	for i := 1; i <= 600; i++ {
+		message := "line"
+		if i == 550 {
+			message = "boom"
+		}
+		input.WriteString(`{"ts":1792404000,"level":"info","message":"` + message + `"}` + "\n")
+	}
+
+	importer, err := NewImporter(ImporterOptions{Store: s, Format: ImportFormatNDJSON, BatchSize: 600})
+	if err != nil {
+		t.Fatalf("unexpected error from NewImporter: %v", err)
+	}
+
+	ctx := context.Background()
+
+	result, err := importer.Import(ctx, strings.NewReader(input.String()))
+	if err == nil {
+		t.Fatal("expected the insert error")
+	}
+
+	if result.Imported != 0 {
+		t.Fatalf("expected nothing imported, got %d", result.Imported)
+	}
+
+	count, err := s.LogCount(ctx, LogQuery())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 0 {
+		t.Fatalf("expected the failed batch to be rolled back, got %d logs", count)
+	}
+}
+
+func Test_Importer_Transaction(t *testing.T) {
+	db := InitDB()
+	db.SetMaxOpenConns(1)
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_import_tx",
+		AutomigrateEnabled: true,
+		Partitioning:       PartitioningDaily,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+	october19 := time.Date(2026, time.October, 19, 10, 0, 0, 0, time.UTC)
+	october20 := october19.AddDate(0, 0, 1)
+
+	// creates the partitions, so the second one can reject the entries
+	for _, at := range []time.Time{october19, october20} {
+		if err := s.Log(NewLog().SetLevel(LEVEL_INFO).SetMessage("existing").SetTime(at)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err = db.Exec(`CREATE TRIGGER reject_boom BEFORE INSERT ON log_import_tx_2026_10_20
+		WHEN NEW.message = 'boom' BEGIN SELECT RAISE(ABORT, 'rejected'); END`)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subscription, err := s.Subscribe(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := `{"ts":1792404000,"level":"info","message":"imported"}` + "\n" +
+		`{"ts":1792490400,"level":"info","message":"boom"}` + "\n"
+
+	importer, err := NewImporter(ImporterOptions{Store: s, Format: ImportFormatNDJSON})
+	if err != nil {
+		t.Fatalf("unexpected error from NewImporter: %v", err)
+	}
+
+	if _, err := importer.Import(ctx, strings.NewReader(input)); err == nil {
+		t.Fatal("expected the insert error")
+	}
+
+	count, err := s.LogCount(ctx, LogQuery().SetMessageContains("imported"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 0 {
+		t.Fatalf("expected the insert into the first partition to be rolled back, got %d logs", count)
+	}
+
+	select {
+	case logEntry := <-subscription:
+		t.Fatalf("expected no entry to be published, got %q", logEntry.GetMessage())
+	default:
+	}
+}
@@ -0,0 +1,44 @@
+// Package storetest provides the store fixture of the integration tests
+package storetest
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/dracory/logstore"
+
+	_ "modernc.org/sqlite"
+)
+
+// NewStore returns a store on an in-memory SQLite database, with the table
+// migrated. The DB and table name of the options default to a new database
+// and "log".
+func NewStore(t testing.TB, opts logstore.NewStoreOptions) logstore.StoreInterface {
+	t.Helper()
+
+	if opts.DB == nil {
+		db, err := sql.Open("sqlite", ":memory:?parseTime=true")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// each connection has a database of its own
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { db.Close() })
+
+		opts.DB = db
+	}
+
+	if opts.LogTableName == "" {
+		opts.LogTableName = "log"
+	}
+
+	opts.AutomigrateEnabled = true
+
+	store, err := logstore.NewStore(opts)
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	return store
+}
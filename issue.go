@@ -0,0 +1,237 @@
+package logstore
+
+import (
+	"cmp"
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+)
+
+// Issue is a group of error, fatal and panic logs sharing a fingerprint
+type Issue struct {
+	// Fingerprint is the grouping key, see Fingerprint
+	Fingerprint string
+
+	// Message is the message of the sample log
+	Message string
+
+	// Count is the number of occurrences, including collapsed duplicates
+	Count int64
+
+	// FirstSeen is the time of the earliest log in the group
+	FirstSeen time.Time
+
+	// LastSeen is the time of the latest log in the group
+	LastSeen time.Time
+
+	// SampleLogID is the ID of one of the logs in the group
+	SampleLogID string
+}
+
+const (
+	issueColumnCount     = "count"
+	issueColumnFirstSeen = "first_seen"
+	issueColumnLastSeen  = "last_seen"
+	issueColumnSampleID  = "sample_id"
+)
+
+// IssueList groups fingerprinted logs matching the query. The query filters
+// select the logs; limit and offset page through the issues, which can be
+// ordered by "count", "first_seen" or "last_seen" (the default), ascending
+// or descending (the default).
+func (st *storeImplementation) IssueList(ctx context.Context, query LogQueryInterface) ([]Issue, error) {
+	if query == nil {
+		query = LogQuery()
+	}
+
+	if err := query.Validate(); err != nil {
+		return []Issue{}, err
+	}
+
+	orderBy := issueColumnLastSeen
+	if query.IsOrderBySet() {
+		switch query.GetOrderBy() {
+		case issueColumnCount, issueColumnFirstSeen, issueColumnLastSeen:
+			orderBy = query.GetOrderBy()
+		}
+	}
+
+	direction := "desc"
+	if query.IsOrderDirectionSet() && strings.EqualFold(query.GetOrderDirection(), "asc") {
+		direction = "asc"
+	}
+
+	var issues []Issue
+
+	if st.partitioning != PartitioningNone {
+		partitioned, err := st.partitionedIssueList(query, orderBy, direction)
+		if err != nil {
+			return []Issue{}, err
+		}
+		issues = partitioned
+	} else {
+		q := st.issueQuery(query, orderBy, direction)
+
+		if query.IsLimitSet() && query.GetLimit() > 0 {
+			q = q.Limit(query.GetLimit())
+		}
+
+		if query.IsOffsetSet() && query.GetOffset() > 0 {
+			q = q.Offset(query.GetOffset())
+		}
+
+		var results []map[string]any
+		if err := q.Get(&results); err != nil {
+			return []Issue{}, err
+		}
+
+		issues = issuesFromResults(results)
+	}
+
+	if len(issues) == 0 {
+		return []Issue{}, nil
+	}
+
+	sampleIDs := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		if issue.SampleLogID != "" {
+			sampleIDs = append(sampleIDs, issue.SampleLogID)
+		}
+	}
+
+	samples, err := st.LogList(ctx, LogQuery().SetIDIn(sampleIDs))
+	if err != nil {
+		return []Issue{}, err
+	}
+
+	messages := map[string]string{}
+	for _, sample := range samples {
+		messages[sample.GetID()] = sample.GetMessage()
+	}
+
+	for i := range issues {
+		issues[i].Message = messages[issues[i].SampleLogID]
+	}
+
+	return issues, nil
+}
+
+// issueQuery groups the fingerprinted logs of the store's table matching
+// the query filters
+func (st *storeImplementation) issueQuery(query LogQueryInterface, orderBy string, direction string) contractsorm.Query {
+	return st.buildFilterQuery(query).
+		Where(COLUMN_FINGERPRINT+" <> ?", "").
+		Select(COLUMN_FINGERPRINT+", "+
+			"SUM(COALESCE("+COLUMN_OCCURRENCES+", 1)) AS "+issueColumnCount+", "+
+			"MIN("+COLUMN_TIME+") AS "+issueColumnFirstSeen+", "+
+			"MAX(COALESCE("+COLUMN_LAST_SEEN+", "+COLUMN_TIME+")) AS "+issueColumnLastSeen+", "+
+			"MAX("+COLUMN_ID+") AS "+issueColumnSampleID).
+		Group(COLUMN_FINGERPRINT).
+		OrderBy(orderBy, direction)
+}
+
+// issuesFromResults converts grouped rows to issues, without messages
+func issuesFromResults(results []map[string]any) []Issue {
+	issues := make([]Issue, 0, len(results))
+
+	for _, result := range results {
+		issue := Issue{
+			Count:     toInt64(result[issueColumnCount]),
+			FirstSeen: toTime(result[issueColumnFirstSeen]),
+			LastSeen:  toTime(result[issueColumnLastSeen]),
+		}
+
+		if v, ok := result[COLUMN_FINGERPRINT].(string); ok {
+			issue.Fingerprint = v
+		}
+
+		if v, ok := result[issueColumnSampleID].(string); ok {
+			issue.SampleLogID = v
+		}
+
+		issues = append(issues, issue)
+	}
+
+	return issues
+}
+
+// partitionedIssueList groups the fingerprinted logs of each partition
+// overlapping the time range of the query, then merges, orders and pages
+// the groups in memory
+func (st *storeImplementation) partitionedIssueList(query LogQueryInterface, orderBy string, direction string) ([]Issue, error) {
+	merged := map[string]*Issue{}
+	order := []string{}
+
+	err := st.forEachPartition(query, func(view *storeImplementation) error {
+		var results []map[string]any
+		if err := view.issueQuery(query, orderBy, direction).Get(&results); err != nil {
+			return err
+		}
+
+		for _, issue := range issuesFromResults(results) {
+			existing, ok := merged[issue.Fingerprint]
+			if !ok {
+				merged[issue.Fingerprint] = &issue
+				order = append(order, issue.Fingerprint)
+				continue
+			}
+
+			existing.Count += issue.Count
+
+			if issue.FirstSeen.Before(existing.FirstSeen) {
+				existing.FirstSeen = issue.FirstSeen
+			}
+
+			// partitions are visited oldest first, so the sample is the latest
+			if !issue.LastSeen.Before(existing.LastSeen) {
+				existing.LastSeen = issue.LastSeen
+				existing.SampleLogID = issue.SampleLogID
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]Issue, 0, len(order))
+	for _, fingerprint := range order {
+		issues = append(issues, *merged[fingerprint])
+	}
+
+	descending := strings.EqualFold(direction, "desc")
+	sort.SliceStable(issues, func(i, j int) bool {
+		var c int
+		switch orderBy {
+		case issueColumnCount:
+			c = cmp.Compare(issues[i].Count, issues[j].Count)
+		case issueColumnFirstSeen:
+			c = issues[i].FirstSeen.Compare(issues[j].FirstSeen)
+		default:
+			c = issues[i].LastSeen.Compare(issues[j].LastSeen)
+		}
+
+		if descending {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	if query.IsOffsetSet() && query.GetOffset() > 0 {
+		if query.GetOffset() >= len(issues) {
+			return []Issue{}, nil
+		}
+		issues = issues[query.GetOffset():]
+	}
+
+	if query.IsLimitSet() && query.GetLimit() > 0 && query.GetLimit() < len(issues) {
+		issues = issues[:query.GetLimit()]
+	}
+
+	return issues, nil
+}
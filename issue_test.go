@@ -0,0 +1,94 @@
+package logstore
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_Store_IssueList(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_issues",
+		AutomigrateEnabled: true,
+		FatalBehavior:      FatalBehaviorReturn,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+
+	for _, message := range []string{"order 1 failed", "order 2 failed", "order 3 failed"} {
+		if err := s.Error(message); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := s.Fatal("config missing"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := s.Info("order 4 done"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	issues, err := s.IssueList(ctx, LogQuery().SetOrderBy("count"))
+	if err != nil {
+		t.Fatalf("unexpected error from IssueList: %v", err)
+	}
+
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(issues))
+	}
+
+	if issues[0].Count != 3 {
+		t.Fatalf("expected the first issue to have 3 occurrences, got %d", issues[0].Count)
+	}
+
+	if issues[0].SampleLogID == "" || issues[0].Message == "" {
+		t.Fatal("expected the issue to have a sample log")
+	}
+
+	if issues[0].FirstSeen.IsZero() || issues[0].LastSeen.IsZero() {
+		t.Fatal("expected the issue to have first and last seen times")
+	}
+
+	logs, err := s.LogList(ctx, LogQuery().SetFingerprint(issues[0].Fingerprint))
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	if len(logs) != 3 {
+		t.Fatalf("expected 3 logs with the issue fingerprint, got %d", len(logs))
+	}
+
+	issues, err = s.IssueList(ctx, LogQuery().SetLevel(LEVEL_FATAL))
+	if err != nil {
+		t.Fatalf("unexpected error from IssueList: %v", err)
+	}
+
+	if len(issues) != 1 || issues[0].Message != "config missing" {
+		t.Fatalf("expected only the fatal issue, got %v", issues)
+	}
+
+	issues, err = s.IssueList(ctx, LogQuery().SetOrderBy("count").SetOrderDirection("ASC"))
+	if err != nil {
+		t.Fatalf("unexpected error from IssueList: %v", err)
+	}
+
+	if len(issues) != 2 || issues[0].Count != 1 {
+		t.Fatalf("expected the issue with 1 occurrence first, got %v", issues)
+	}
+
+	issues, err = s.IssueList(ctx, LogQuery().SetOrderBy("count").SetOrderDirection("asc, fingerprint"))
+	if err != nil {
+		t.Fatalf("expected an invalid direction to default to desc, got %v", err)
+	}
+
+	if len(issues) != 2 || issues[0].Count != 3 {
+		t.Fatalf("expected the issue with 3 occurrences first, got %v", issues)
+	}
+}
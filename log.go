@@ -26,15 +26,82 @@ type LogInterface interface {
 
 	GetTimeCarbon() *carbon.Carbon
 	SetTimeCarbon(t *carbon.Carbon) LogInterface
+
+	// GetOccurrences returns how many identical entries this row stands for
+	GetOccurrences() int
+	SetOccurrences(occurrences int) LogInterface
+
+	// GetLastSeen returns the time of the last collapsed occurrence
+	GetLastSeen() time.Time
+	SetLastSeen(t time.Time) LogInterface
+
+	// GetFingerprint returns the issue grouping key of error level entries
+	GetFingerprint() string
+	SetFingerprint(fingerprint string) LogInterface
+
+	// GetStack returns the stack trace captured when the entry was logged
+	GetStack() string
+	SetStack(stack string) LogInterface
+
+	// GetTraceID returns the distributed trace the entry belongs to
+	GetTraceID() string
+	SetTraceID(traceID string) LogInterface
+
+	// GetSpanID returns the span within the trace the entry belongs to
+	GetSpanID() string
+	SetSpanID(spanID string) LogInterface
+
+	// GetRequestID returns the request the entry belongs to
+	GetRequestID() string
+	SetRequestID(requestID string) LogInterface
+
+	// GetServiceName returns the name of the service which wrote the entry
+	GetServiceName() string
+	SetServiceName(serviceName string) LogInterface
+
+	// GetServiceVersion returns the version of the service which wrote the entry
+	GetServiceVersion() string
+	SetServiceVersion(serviceVersion string) LogInterface
+
+	// GetHostName returns the host the entry was written on
+	GetHostName() string
+	SetHostName(hostName string) LogInterface
+
+	// GetEnvironment returns the environment (i.e. production) the entry was written in
+	GetEnvironment() string
+	SetEnvironment(environment string) LogInterface
+
+	// GetInstanceID returns the service instance which wrote the entry
+	GetInstanceID() string
+	SetInstanceID(instanceID string) LogInterface
+
+	// GetTenantID returns the tenant the entry belongs to
+	GetTenantID() string
+	SetTenantID(tenantID string) LogInterface
 }
 
 // logImplementation is the concrete implementation of LogInterface
 type logImplementation struct {
-	id      string
-	level   string
-	message string
-	context string
-	time    time.Time
+	id          string
+	level       string
+	message     string
+	context     string
+	time        time.Time
+	occurrences int
+	lastSeen    time.Time
+	fingerprint string
+	stack       string
+	traceID     string
+	spanID      string
+	requestID   string
+
+	serviceName    string
+	serviceVersion string
+	hostName       string
+	environment    string
+	instanceID     string
+
+	tenantID string
 }
 
 var _ LogInterface = (*logImplementation)(nil)
@@ -42,22 +109,24 @@ var _ LogInterface = (*logImplementation)(nil)
 // NewLog creates a new log with the current UTC time
 func NewLog() LogInterface {
 	return &logImplementation{
-		id:      neatuid.GenerateShortID(),
-		level:   LEVEL_INFO,
-		message: "",
-		context: "",
-		time:    time.Now().UTC(),
+		id:          neatuid.GenerateShortID(),
+		level:       LEVEL_INFO,
+		message:     "",
+		context:     "",
+		time:        time.Now().UTC(),
+		occurrences: 1,
 	}
 }
 
 // NewLogWithData creates a new log from existing values
 func NewLogWithData(id, level, message, context string, t time.Time) LogInterface {
 	return &logImplementation{
-		id:      id,
-		level:   level,
-		message: message,
-		context: context,
-		time:    t,
+		id:          id,
+		level:       level,
+		message:     message,
+		context:     context,
+		time:        t,
+		occurrences: 1,
 	}
 }
 
@@ -114,3 +183,120 @@ func (l *logImplementation) SetTimeCarbon(t *carbon.Carbon) LogInterface {
 	l.time = t.StdTime()
 	return l
 }
+
+func (l *logImplementation) GetOccurrences() int {
+	return l.occurrences
+}
+
+func (l *logImplementation) SetOccurrences(occurrences int) LogInterface {
+	l.occurrences = occurrences
+	return l
+}
+
+func (l *logImplementation) GetLastSeen() time.Time {
+	return l.lastSeen
+}
+
+func (l *logImplementation) SetLastSeen(t time.Time) LogInterface {
+	l.lastSeen = t
+	return l
+}
+
+func (l *logImplementation) GetFingerprint() string {
+	return l.fingerprint
+}
+
+func (l *logImplementation) SetFingerprint(fingerprint string) LogInterface {
+	l.fingerprint = fingerprint
+	return l
+}
+
+func (l *logImplementation) GetStack() string {
+	return l.stack
+}
+
+func (l *logImplementation) SetStack(stack string) LogInterface {
+	l.stack = stack
+	return l
+}
+
+func (l *logImplementation) GetTraceID() string {
+	return l.traceID
+}
+
+func (l *logImplementation) SetTraceID(traceID string) LogInterface {
+	l.traceID = traceID
+	return l
+}
+
+func (l *logImplementation) GetSpanID() string {
+	return l.spanID
+}
+
+func (l *logImplementation) SetSpanID(spanID string) LogInterface {
+	l.spanID = spanID
+	return l
+}
+
+func (l *logImplementation) GetRequestID() string {
+	return l.requestID
+}
+
+func (l *logImplementation) SetRequestID(requestID string) LogInterface {
+	l.requestID = requestID
+	return l
+}
+
+func (l *logImplementation) GetServiceName() string {
+	return l.serviceName
+}
+
+func (l *logImplementation) SetServiceName(serviceName string) LogInterface {
+	l.serviceName = serviceName
+	return l
+}
+
+func (l *logImplementation) GetServiceVersion() string {
+	return l.serviceVersion
+}
+
+func (l *logImplementation) SetServiceVersion(serviceVersion string) LogInterface {
+	l.serviceVersion = serviceVersion
+	return l
+}
+
+func (l *logImplementation) GetHostName() string {
+	return l.hostName
+}
+
+func (l *logImplementation) SetHostName(hostName string) LogInterface {
+	l.hostName = hostName
+	return l
+}
+
+func (l *logImplementation) GetEnvironment() string {
+	return l.environment
+}
+
+func (l *logImplementation) SetEnvironment(environment string) LogInterface {
+	l.environment = environment
+	return l
+}
+
+func (l *logImplementation) GetInstanceID() string {
+	return l.instanceID
+}
+
+func (l *logImplementation) SetInstanceID(instanceID string) LogInterface {
+	l.instanceID = instanceID
+	return l
+}
+
+func (l *logImplementation) GetTenantID() string {
+	return l.tenantID
+}
+
+func (l *logImplementation) SetTenantID(tenantID string) LogInterface {
+	l.tenantID = tenantID
+	return l
+}
@@ -1,6 +1,9 @@
 package logstore
 
-import "errors"
+import (
+	"errors"
+	"slices"
+)
 
 // LogQueryInterface defines the interface for querying logs
 type LogQueryInterface interface {
@@ -68,6 +71,46 @@ type LogQueryInterface interface {
 	IsColumnsSet() bool
 	GetColumns() []string
 	SetColumns(columns []string) LogQueryInterface
+
+	IsFingerprintSet() bool
+	GetFingerprint() string
+	SetFingerprint(fingerprint string) LogQueryInterface
+
+	IsTraceIDSet() bool
+	GetTraceID() string
+	SetTraceID(traceID string) LogQueryInterface
+
+	IsSpanIDSet() bool
+	GetSpanID() string
+	SetSpanID(spanID string) LogQueryInterface
+
+	IsRequestIDSet() bool
+	GetRequestID() string
+	SetRequestID(requestID string) LogQueryInterface
+
+	IsServiceNameSet() bool
+	GetServiceName() string
+	SetServiceName(serviceName string) LogQueryInterface
+
+	IsServiceVersionSet() bool
+	GetServiceVersion() string
+	SetServiceVersion(serviceVersion string) LogQueryInterface
+
+	IsHostNameSet() bool
+	GetHostName() string
+	SetHostName(hostName string) LogQueryInterface
+
+	IsEnvironmentSet() bool
+	GetEnvironment() string
+	SetEnvironment(environment string) LogQueryInterface
+
+	IsInstanceIDSet() bool
+	GetInstanceID() string
+	SetInstanceID(instanceID string) LogQueryInterface
+
+	IsTenantIDSet() bool
+	GetTenantID() string
+	SetTenantID(tenantID string) LogQueryInterface
 }
 
 // logQueryImplementation implements the LogQueryInterface
@@ -114,6 +157,36 @@ type logQueryImplementation struct {
 
 	isColumnsSet bool
 	columns      []string
+
+	isFingerprintSet bool
+	fingerprint      string
+
+	isTraceIDSet bool
+	traceID      string
+
+	isSpanIDSet bool
+	spanID      string
+
+	isRequestIDSet bool
+	requestID      string
+
+	isServiceNameSet bool
+	serviceName      string
+
+	isServiceVersionSet bool
+	serviceVersion      string
+
+	isHostNameSet bool
+	hostName      string
+
+	isEnvironmentSet bool
+	environment      string
+
+	isInstanceIDSet bool
+	instanceID      string
+
+	isTenantIDSet bool
+	tenantID      string
 }
 
 var _ LogQueryInterface = (*logQueryImplementation)(nil)
@@ -157,6 +230,46 @@ func (q *logQueryImplementation) Validate() error {
 		return errors.New("log query: context_not_contains cannot be empty")
 	}
 
+	if q.IsFingerprintSet() && q.GetFingerprint() == "" {
+		return errors.New("log query: fingerprint cannot be empty")
+	}
+
+	if q.IsTraceIDSet() && q.GetTraceID() == "" {
+		return errors.New("log query: trace_id cannot be empty")
+	}
+
+	if q.IsSpanIDSet() && q.GetSpanID() == "" {
+		return errors.New("log query: span_id cannot be empty")
+	}
+
+	if q.IsRequestIDSet() && q.GetRequestID() == "" {
+		return errors.New("log query: request_id cannot be empty")
+	}
+
+	if q.IsServiceNameSet() && q.GetServiceName() == "" {
+		return errors.New("log query: service_name cannot be empty")
+	}
+
+	if q.IsServiceVersionSet() && q.GetServiceVersion() == "" {
+		return errors.New("log query: service_version cannot be empty")
+	}
+
+	if q.IsHostNameSet() && q.GetHostName() == "" {
+		return errors.New("log query: host_name cannot be empty")
+	}
+
+	if q.IsEnvironmentSet() && q.GetEnvironment() == "" {
+		return errors.New("log query: environment cannot be empty")
+	}
+
+	if q.IsInstanceIDSet() && q.GetInstanceID() == "" {
+		return errors.New("log query: instance_id cannot be empty")
+	}
+
+	if q.IsTenantIDSet() && q.GetTenantID() == "" {
+		return errors.New("log query: tenant_id cannot be empty")
+	}
+
 	if q.IsLimitSet() && q.GetLimit() < 0 {
 		return errors.New("log query: limit cannot be negative")
 	}
@@ -426,3 +539,199 @@ func (q *logQueryImplementation) SetColumns(columns []string) LogQueryInterface
 	q.columns = columns
 	return q
 }
+
+func (q *logQueryImplementation) IsFingerprintSet() bool {
+	return q.isFingerprintSet
+}
+
+func (q *logQueryImplementation) GetFingerprint() string {
+	if q.IsFingerprintSet() {
+		return q.fingerprint
+	}
+	return ""
+}
+
+func (q *logQueryImplementation) SetFingerprint(fingerprint string) LogQueryInterface {
+	q.isFingerprintSet = true
+	q.fingerprint = fingerprint
+	return q
+}
+
+func (q *logQueryImplementation) IsTraceIDSet() bool {
+	return q.isTraceIDSet
+}
+
+func (q *logQueryImplementation) GetTraceID() string {
+	if q.IsTraceIDSet() {
+		return q.traceID
+	}
+	return ""
+}
+
+func (q *logQueryImplementation) SetTraceID(traceID string) LogQueryInterface {
+	q.isTraceIDSet = true
+	q.traceID = traceID
+	return q
+}
+
+func (q *logQueryImplementation) IsSpanIDSet() bool {
+	return q.isSpanIDSet
+}
+
+func (q *logQueryImplementation) GetSpanID() string {
+	if q.IsSpanIDSet() {
+		return q.spanID
+	}
+	return ""
+}
+
+func (q *logQueryImplementation) SetSpanID(spanID string) LogQueryInterface {
+	q.isSpanIDSet = true
+	q.spanID = spanID
+	return q
+}
+
+func (q *logQueryImplementation) IsRequestIDSet() bool {
+	return q.isRequestIDSet
+}
+
+func (q *logQueryImplementation) GetRequestID() string {
+	if q.IsRequestIDSet() {
+		return q.requestID
+	}
+	return ""
+}
+
+func (q *logQueryImplementation) SetRequestID(requestID string) LogQueryInterface {
+	q.isRequestIDSet = true
+	q.requestID = requestID
+	return q
+}
+
+func (q *logQueryImplementation) IsServiceNameSet() bool {
+	return q.isServiceNameSet
+}
+
+func (q *logQueryImplementation) GetServiceName() string {
+	if q.IsServiceNameSet() {
+		return q.serviceName
+	}
+	return ""
+}
+
+func (q *logQueryImplementation) SetServiceName(serviceName string) LogQueryInterface {
+	q.isServiceNameSet = true
+	q.serviceName = serviceName
+	return q
+}
+
+func (q *logQueryImplementation) IsServiceVersionSet() bool {
+	return q.isServiceVersionSet
+}
+
+func (q *logQueryImplementation) GetServiceVersion() string {
+	if q.IsServiceVersionSet() {
+		return q.serviceVersion
+	}
+	return ""
+}
+
+func (q *logQueryImplementation) SetServiceVersion(serviceVersion string) LogQueryInterface {
+	q.isServiceVersionSet = true
+	q.serviceVersion = serviceVersion
+	return q
+}
+
+func (q *logQueryImplementation) IsHostNameSet() bool {
+	return q.isHostNameSet
+}
+
+func (q *logQueryImplementation) GetHostName() string {
+	if q.IsHostNameSet() {
+		return q.hostName
+	}
+	return ""
+}
+
+func (q *logQueryImplementation) SetHostName(hostName string) LogQueryInterface {
+	q.isHostNameSet = true
+	q.hostName = hostName
+	return q
+}
+
+func (q *logQueryImplementation) IsEnvironmentSet() bool {
+	return q.isEnvironmentSet
+}
+
+func (q *logQueryImplementation) GetEnvironment() string {
+	if q.IsEnvironmentSet() {
+		return q.environment
+	}
+	return ""
+}
+
+func (q *logQueryImplementation) SetEnvironment(environment string) LogQueryInterface {
+	q.isEnvironmentSet = true
+	q.environment = environment
+	return q
+}
+
+func (q *logQueryImplementation) IsInstanceIDSet() bool {
+	return q.isInstanceIDSet
+}
+
+func (q *logQueryImplementation) GetInstanceID() string {
+	if q.IsInstanceIDSet() {
+		return q.instanceID
+	}
+	return ""
+}
+
+func (q *logQueryImplementation) SetInstanceID(instanceID string) LogQueryInterface {
+	q.isInstanceIDSet = true
+	q.instanceID = instanceID
+	return q
+}
+
+func (q *logQueryImplementation) IsTenantIDSet() bool {
+	return q.isTenantIDSet
+}
+
+func (q *logQueryImplementation) GetTenantID() string {
+	if q.IsTenantIDSet() {
+		return q.tenantID
+	}
+	return ""
+}
+
+func (q *logQueryImplementation) SetTenantID(tenantID string) LogQueryInterface {
+	q.isTenantIDSet = true
+	q.tenantID = tenantID
+	return q
+}
+
+// orderColumns lists the columns the logs can be ordered by
+var orderColumns = []string{
+	COLUMN_ENVIRONMENT,
+	COLUMN_FINGERPRINT,
+	COLUMN_HOST_NAME,
+	COLUMN_ID,
+	COLUMN_INSTANCE_ID,
+	COLUMN_LAST_SEEN,
+	COLUMN_LEVEL,
+	COLUMN_MESSAGE,
+	COLUMN_OCCURRENCES,
+	COLUMN_REQUEST_ID,
+	COLUMN_SERVICE_NAME,
+	COLUMN_SERVICE_VERSION,
+	COLUMN_SPAN_ID,
+	COLUMN_TENANT_ID,
+	COLUMN_TIME,
+	COLUMN_TRACE_ID,
+}
+
+// IsOrderColumn reports whether the logs can be ordered by the column, so
+// callers passing an order from user input can check it first
+func IsOrderColumn(column string) bool {
+	return slices.Contains(orderColumns, column)
+}
@@ -0,0 +1,28 @@
+module github.com/dracory/logstore/logrusstore
+
+go 1.26.3
+
+require (
+	github.com/dracory/logstore v0.0.0-20261019090621-795b29fc1874
+	github.com/sirupsen/logrus v1.10.2
+)
+
+require (
+	github.com/dracory/neat v0.27.0 // indirect
+	github.com/dromara/carbon/v2 v2.6.16 // indirect
+	github.com/dustin/go-humanize v1.0.1 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/klauspost/compress v1.20.1 // indirect
+	github.com/mattn/go-isatty v0.0.22 // indirect
+	github.com/ncruces/go-strftime v1.0.0 // indirect
+	github.com/remyoudompheng/bigfft v0.0.0-20230129092748-24d4a6f8daec // indirect
+	github.com/samber/lo v1.53.0 // indirect
+	github.com/spf13/cast v1.10.0 // indirect
+	golang.org/x/exp v0.0.0-20260611194520-c48552f49976 // indirect
+	golang.org/x/sys v0.48.0 // indirect
+	golang.org/x/text v0.40.0 // indirect
+	modernc.org/libc v1.73.5 // indirect
+	modernc.org/mathutil v1.7.1 // indirect
+	modernc.org/memory v1.11.0 // indirect
+	modernc.org/sqlite v1.53.0 // indirect
+)
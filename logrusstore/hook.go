@@ -0,0 +1,112 @@
+// Package logrusstore provides a logrus hook writing entries to a log store,
+// so services using logrus can adopt logstore without changing their
+// logging calls.
+package logrusstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dracory/logstore"
+	"github.com/sirupsen/logrus"
+)
+
+// Options define the options of NewHook
+type Options struct {
+	// Levels are the levels written to the store, by default all of them
+	Levels []logrus.Level
+}
+
+// Hook adds the logrus entries of its levels to a log store
+type Hook struct {
+	store  logstore.StoreInterface
+	levels []logrus.Level
+}
+
+var _ logrus.Hook = (*Hook)(nil)
+
+// NewHook returns a hook adding each logrus entry to the store, with the
+// entry data encoded as the context JSON. The correlation IDs are taken
+// from the context of entries logged WithContext.
+//
+// Fatal and panic entries are only logged: logrus itself exits or panics
+// after firing the hooks. They flush the store, so they are not lost in its
+// deduplication.
+func NewHook(store logstore.StoreInterface, opts ...Options) *Hook {
+	h := &Hook{store: store, levels: logrus.AllLevels}
+
+	if len(opts) > 0 && len(opts[0].Levels) > 0 {
+		h.levels = opts[0].Levels
+	}
+
+	return h
+}
+
+func (h *Hook) Levels() []logrus.Level {
+	return h.levels
+}
+
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	logEntry := logstore.NewLog().
+		SetLevel(levelOf(entry.Level)).
+		SetMessage(entry.Message).
+		SetTime(entry.Time.UTC())
+
+	data := make(map[string]any, len(entry.Data)+1)
+	for key, value := range entry.Data {
+		// errors have no exported fields, and would be encoded as {}
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		data[key] = value
+	}
+
+	if entry.HasCaller() {
+		data["caller"] = fmt.Sprintf("%s:%d", entry.Caller.File, entry.Caller.Line)
+	}
+
+	if len(data) > 0 {
+		contextBytes, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		logEntry.SetContext(string(contextBytes))
+	}
+
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := h.store.LogCtx(ctx, logEntry); err != nil {
+		return err
+	}
+
+	// logrus exits or panics next
+	if entry.Level <= logrus.FatalLevel {
+		return h.store.Flush(ctx)
+	}
+
+	return nil
+}
+
+// levelOf maps a logrus level to a log level
+func levelOf(level logrus.Level) string {
+	switch level {
+	case logrus.PanicLevel:
+		return logstore.LEVEL_PANIC
+	case logrus.FatalLevel:
+		return logstore.LEVEL_FATAL
+	case logrus.ErrorLevel:
+		return logstore.LEVEL_ERROR
+	case logrus.WarnLevel:
+		return logstore.LEVEL_WARNING
+	case logrus.InfoLevel:
+		return logstore.LEVEL_INFO
+	case logrus.DebugLevel:
+		return logstore.LEVEL_DEBUG
+	}
+
+	return logstore.LEVEL_TRACE
+}
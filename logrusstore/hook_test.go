@@ -0,0 +1,122 @@
+package logrusstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/dracory/logstore"
+	"github.com/dracory/logstore/internal/storetest"
+	"github.com/sirupsen/logrus"
+)
+
+func Test_Hook(t *testing.T) {
+	store := storetest.NewStore(t, logstore.NewStoreOptions{LogTableName: "log_logrus"})
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetLevel(logrus.TraceLevel)
+	logger.AddHook(NewHook(store, Options{
+		Levels: []logrus.Level{logrus.ErrorLevel, logrus.WarnLevel},
+	}))
+
+	at := time.Date(2026, time.October, 19, 14, 0, 0, 0, time.UTC)
+	ctx := logstore.ContextWithCorrelationIDs(context.Background(), logstore.CorrelationIDs{TraceID: "trace-1"})
+
+	logger.WithContext(ctx).
+		WithTime(at).
+		WithFields(logrus.Fields{"user_id": "u-1", "attempt": 2}).
+		WithError(errors.New("card declined")).
+		Error("payment failed")
+
+	logger.Warn("slow query")
+	logger.Info("not hooked")
+
+	logs, err := store.LogList(context.Background(), logstore.LogQuery().
+		SetOrderBy(logstore.COLUMN_LEVEL).
+		SetOrderDirection("asc"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 logs, got %d", len(logs))
+	}
+
+	logEntry := logs[0]
+
+	if logEntry.GetLevel() != logstore.LEVEL_ERROR || logEntry.GetMessage() != "payment failed" {
+		t.Fatalf("unexpected log %s %q", logEntry.GetLevel(), logEntry.GetMessage())
+	}
+
+	if !logEntry.GetTime().Equal(at) {
+		t.Fatalf("expected the entry time, got %v", logEntry.GetTime())
+	}
+
+	if logEntry.GetTraceID() != "trace-1" {
+		t.Fatalf("expected the trace ID of the context, got %q", logEntry.GetTraceID())
+	}
+
+	fields := map[string]any{}
+	if err := json.Unmarshal([]byte(logEntry.GetContext()), &fields); err != nil {
+		t.Fatalf("expected JSON context: %v", err)
+	}
+
+	if fields["user_id"] != "u-1" || fields["attempt"] != float64(2) || fields[logrus.ErrorKey] != "card declined" {
+		t.Fatalf("unexpected context: %v", fields)
+	}
+
+	if logs[1].GetLevel() != logstore.LEVEL_WARNING {
+		t.Fatalf("expected a warning, got %s", logs[1].GetLevel())
+	}
+}
+
+func Test_levelOf(t *testing.T) {
+	levels := map[logrus.Level]string{
+		logrus.TraceLevel: logstore.LEVEL_TRACE,
+		logrus.DebugLevel: logstore.LEVEL_DEBUG,
+		logrus.InfoLevel:  logstore.LEVEL_INFO,
+		logrus.WarnLevel:  logstore.LEVEL_WARNING,
+		logrus.ErrorLevel: logstore.LEVEL_ERROR,
+		logrus.FatalLevel: logstore.LEVEL_FATAL,
+		logrus.PanicLevel: logstore.LEVEL_PANIC,
+	}
+
+	for level, expected := range levels {
+		if actual := levelOf(level); actual != expected {
+			t.Errorf("expected %s for %s, got %s", expected, level, actual)
+		}
+	}
+}
+
+func Test_Hook_FlushesFatalAndPanic(t *testing.T) {
+	store := storetest.NewStore(t, logstore.NewStoreOptions{
+		LogTableName:        "log_logrus_dedup",
+		DeduplicationWindow: time.Hour,
+	})
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.ExitFunc = func(int) {}
+	logger.AddHook(NewHook(store))
+
+	logger.Fatal("fatal")
+
+	func() {
+		defer func() { recover() }()
+		logger.Panic("panic")
+	}()
+
+	count, err := store.LogCount(context.Background(), logstore.LogQuery())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 2 {
+		t.Fatalf("expected the fatal and panic entries to be flushed, got %d", count)
+	}
+}
@@ -0,0 +1,238 @@
+// Package otelstore bridges logstore and OpenTelemetry: an OpenTelemetry
+// log exporter adding the records of the OTel SDK to a log store, and an
+// OTLP exporter sending the stored entries to an OTLP/HTTP collector.
+package otelstore
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+
+	"github.com/dracory/logstore"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// Resource and record attributes stored in columns of their own
+const (
+	attributeServiceName           = "service.name"
+	attributeServiceVersion        = "service.version"
+	attributeHostName              = "host.name"
+	attributeEnvironment           = "deployment.environment.name"
+	attributeEnvironmentDeprecated = "deployment.environment"
+	attributeInstanceID            = "service.instance.id"
+	attributeStack                 = "exception.stacktrace"
+	attributeRequestID             = "request_id"
+)
+
+// Context keys of the record details without a column of their own
+const (
+	contextScopeName    = "otel.scope.name"
+	contextScopeVersion = "otel.scope.version"
+	contextEventName    = "otel.event_name"
+	contextResource     = "otel.resource"
+)
+
+// Exporter is an OpenTelemetry log exporter adding the records to a log
+// store, for use with the processors of the OTel SDK
+type Exporter struct {
+	store   logstore.StoreInterface
+	stopped atomic.Bool
+}
+
+var _ sdklog.Exporter = (*Exporter)(nil)
+
+// NewExporter returns an exporter adding each record to the store:
+//   - the severity as the level, and the body as the message
+//   - the trace and span IDs of the record
+//   - the service name, version, host name, environment and instance ID of
+//     the resource, and the "exception.stacktrace" and "request_id"
+//     attributes, to their columns
+//   - the other attributes as the context, along with the instrumentation
+//     scope, event name and other resource attributes under "otel." keys
+//
+// Records are written in bulk, one insert per export.
+func NewExporter(store logstore.StoreInterface) *Exporter {
+	return &Exporter{store: store}
+}
+
+// Export adds the records to the store
+func (e *Exporter) Export(ctx context.Context, records []sdklog.Record) error {
+	if e.stopped.Load() || len(records) == 0 {
+		return nil
+	}
+
+	logEntries := make([]logstore.LogInterface, 0, len(records))
+	for i := range records {
+		logEntries = append(logEntries, logOf(&records[i]))
+	}
+
+	// the stack of the processor goroutine says nothing about the records
+	return e.store.LogCreateMany(logstore.ContextWithoutStackCapture(ctx), logEntries)
+}
+
+// Shutdown stops the exporter, dropping the records exported afterwards
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	e.stopped.Store(true)
+	return nil
+}
+
+// ForceFlush writes any entries held back by the store
+func (e *Exporter) ForceFlush(ctx context.Context) error {
+	return e.store.Flush(ctx)
+}
+
+// logOf converts a record to a log entry
+func logOf(record *sdklog.Record) logstore.LogInterface {
+	logEntry := logstore.NewLog().
+		SetLevel(levelOf(record.Severity(), record.SeverityText())).
+		SetMessage(record.Body().String())
+
+	if t := record.Timestamp(); !t.IsZero() {
+		logEntry.SetTime(t.UTC())
+	} else if t := record.ObservedTimestamp(); !t.IsZero() {
+		logEntry.SetTime(t.UTC())
+	}
+
+	if traceID := record.TraceID(); traceID.IsValid() {
+		logEntry.SetTraceID(traceID.String())
+	}
+
+	if spanID := record.SpanID(); spanID.IsValid() {
+		logEntry.SetSpanID(spanID.String())
+	}
+
+	fields := map[string]any{}
+
+	record.WalkAttributes(func(kv attribute.KeyValue) bool {
+		switch {
+		case kv.Key == attributeStack && kv.Value.Type() == attribute.STRING:
+			logEntry.SetStack(kv.Value.AsString())
+		case kv.Key == attributeRequestID && kv.Value.Type() == attribute.STRING:
+			logEntry.SetRequestID(kv.Value.AsString())
+		default:
+			fields[string(kv.Key)] = valueOf(kv.Value)
+		}
+		return true
+	})
+
+	if scope := record.InstrumentationScope(); scope.Name != "" {
+		fields[contextScopeName] = scope.Name
+		if scope.Version != "" {
+			fields[contextScopeVersion] = scope.Version
+		}
+	}
+
+	if eventName := record.EventName(); eventName != "" {
+		fields[contextEventName] = eventName
+	}
+
+	if resourceFields := applyResource(logEntry, record); len(resourceFields) > 0 {
+		fields[contextResource] = resourceFields
+	}
+
+	if len(fields) > 0 {
+		if contextBytes, err := json.Marshal(fields); err == nil {
+			logEntry.SetContext(string(contextBytes))
+		}
+	}
+
+	return logEntry
+}
+
+// applyResource sets the resource columns of the entry, and returns the
+// other resource attributes
+func applyResource(logEntry logstore.LogInterface, record *sdklog.Record) map[string]any {
+	resource := record.Resource()
+	if resource == nil {
+		return nil
+	}
+
+	fields := map[string]any{}
+
+	for _, kv := range resource.Attributes() {
+		value := kv.Value.Emit()
+
+		switch string(kv.Key) {
+		case attributeServiceName:
+			// the SDK default, so the resource of the store applies instead
+			if !strings.HasPrefix(value, "unknown_service") {
+				logEntry.SetServiceName(value)
+			}
+		case attributeServiceVersion:
+			logEntry.SetServiceVersion(value)
+		case attributeHostName:
+			logEntry.SetHostName(value)
+		case attributeEnvironment, attributeEnvironmentDeprecated:
+			logEntry.SetEnvironment(value)
+		case attributeInstanceID:
+			logEntry.SetInstanceID(value)
+		default:
+			fields[string(kv.Key)] = valueOf(kv.Value)
+		}
+	}
+
+	return fields
+}
+
+// valueOf converts an attribute value to its JSON representation
+func valueOf(value attribute.Value) any {
+	switch value.Type() {
+	case attribute.SLICE:
+		values := []any{}
+		for _, item := range value.AsSlice() {
+			values = append(values, valueOf(item))
+		}
+		return values
+	case attribute.MAP:
+		values := map[string]any{}
+		for _, kv := range value.AsMap() {
+			values[string(kv.Key)] = valueOf(kv.Value)
+		}
+		return values
+	}
+
+	return value.AsInterface()
+}
+
+// levelOf maps an OpenTelemetry severity to a log level. Fatal records with
+// the severity text "panic" are panics, and records without a severity take
+// the level named by their severity text, or info.
+func levelOf(severity log.Severity, text string) string {
+	switch {
+	case severity >= log.SeverityFatal1:
+		if strings.EqualFold(text, logstore.LEVEL_PANIC) {
+			return logstore.LEVEL_PANIC
+		}
+		return logstore.LEVEL_FATAL
+	case severity >= log.SeverityError1:
+		return logstore.LEVEL_ERROR
+	case severity >= log.SeverityWarn1:
+		return logstore.LEVEL_WARNING
+	case severity >= log.SeverityInfo1:
+		return logstore.LEVEL_INFO
+	case severity >= log.SeverityDebug1:
+		return logstore.LEVEL_DEBUG
+	case severity >= log.SeverityTrace1:
+		return logstore.LEVEL_TRACE
+	}
+
+	switch strings.ToLower(text) {
+	case logstore.LEVEL_TRACE:
+		return logstore.LEVEL_TRACE
+	case logstore.LEVEL_DEBUG:
+		return logstore.LEVEL_DEBUG
+	case "warn", logstore.LEVEL_WARNING:
+		return logstore.LEVEL_WARNING
+	case logstore.LEVEL_ERROR:
+		return logstore.LEVEL_ERROR
+	case logstore.LEVEL_FATAL:
+		return logstore.LEVEL_FATAL
+	case logstore.LEVEL_PANIC:
+		return logstore.LEVEL_PANIC
+	}
+
+	return logstore.LEVEL_INFO
+}
@@ -0,0 +1,187 @@
+package otelstore
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dracory/logstore"
+	"github.com/dracory/logstore/internal/storetest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// emitRecords emits the records of the tests through the OTel SDK
+func emitRecords(t *testing.T, store logstore.StoreInterface) {
+	t.Helper()
+
+	res := resource.NewSchemaless(
+		attribute.String("service.name", "billing"),
+		attribute.String("service.version", "1.4.2"),
+		attribute.String("deployment.environment.name", "production"),
+		attribute.String("cloud.region", "eu-west-1"),
+	)
+
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewSimpleProcessor(NewExporter(store))),
+	)
+
+	logger := provider.Logger("billing/invoices", log.WithInstrumentationVersion("0.3.0"))
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanContext)
+
+	record := log.Record{}
+	record.SetTimestamp(time.Date(2026, 10, 19, 14, 0, 0, 0, time.UTC))
+	record.SetSeverity(log.SeverityWarn)
+	record.SetBody(attribute.StringValue("invoice overdue"))
+	record.AddAttributes(
+		attribute.String("invoice", "inv-1"),
+		attribute.Int("days", 12),
+		attribute.Map("customer", attribute.String("id", "c-1")),
+		attribute.String("request_id", "req-1"),
+		attribute.String("exception.stacktrace", "main.main()"),
+	)
+	logger.Emit(ctx, record)
+
+	record = log.Record{}
+	record.SetTimestamp(time.Date(2026, 10, 19, 14, 0, 1, 0, time.UTC))
+	record.SetSeverity(log.SeverityFatal)
+	record.SetSeverityText("panic")
+	record.SetEventName("invoice.failed")
+	record.SetBody(attribute.MapValue(attribute.String("reason", "timeout")))
+	logger.Emit(context.Background(), record)
+
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_Exporter(t *testing.T) {
+	store := storetest.NewStore(t, logstore.NewStoreOptions{LogTableName: "log_otel"})
+	emitRecords(t, store)
+
+	logs, err := store.LogList(context.Background(), logstore.LogQuery().
+		SetOrderBy(logstore.COLUMN_TIME).
+		SetOrderDirection("asc"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 logs, got %d", len(logs))
+	}
+
+	logEntry := logs[0]
+
+	if logEntry.GetLevel() != logstore.LEVEL_WARNING || logEntry.GetMessage() != "invoice overdue" {
+		t.Fatalf("unexpected log %q %q", logEntry.GetLevel(), logEntry.GetMessage())
+	}
+
+	if !logEntry.GetTime().Equal(time.Date(2026, 10, 19, 14, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected time %v", logEntry.GetTime())
+	}
+
+	if logEntry.GetTraceID() != "4bf92f3577b34da6a3ce929d0e0e4736" || logEntry.GetSpanID() != "00f067aa0ba902b7" {
+		t.Fatalf("unexpected trace %q and span %q", logEntry.GetTraceID(), logEntry.GetSpanID())
+	}
+
+	if logEntry.GetServiceName() != "billing" || logEntry.GetServiceVersion() != "1.4.2" || logEntry.GetEnvironment() != "production" {
+		t.Fatalf("unexpected resource %q %q %q", logEntry.GetServiceName(), logEntry.GetServiceVersion(), logEntry.GetEnvironment())
+	}
+
+	if logEntry.GetRequestID() != "req-1" || logEntry.GetStack() != "main.main()" {
+		t.Fatalf("unexpected request ID %q and stack %q", logEntry.GetRequestID(), logEntry.GetStack())
+	}
+
+	fields := map[string]any{}
+	if err := json.Unmarshal([]byte(logEntry.GetContext()), &fields); err != nil {
+		t.Fatal(err)
+	}
+
+	if fields["invoice"] != "inv-1" || fields["days"] != float64(12) || fields[contextScopeName] != "billing/invoices" || fields[contextScopeVersion] != "0.3.0" {
+		t.Fatalf("unexpected context: %v", fields)
+	}
+
+	if customer, _ := fields["customer"].(map[string]any); customer["id"] != "c-1" {
+		t.Fatalf("expected the map attribute: %v", fields)
+	}
+
+	if res, _ := fields[contextResource].(map[string]any); res["cloud.region"] != "eu-west-1" || res["service.name"] != nil {
+		t.Fatalf("expected the other resource attributes: %v", fields)
+	}
+
+	if _, ok := fields["request_id"]; ok {
+		t.Fatalf("expected the request ID to be taken out of the context: %v", fields)
+	}
+
+	panicEntry := logs[1]
+
+	if panicEntry.GetLevel() != logstore.LEVEL_PANIC || panicEntry.GetMessage() != `{"reason":"timeout"}` {
+		t.Fatalf("unexpected log %q %q", panicEntry.GetLevel(), panicEntry.GetMessage())
+	}
+
+	if panicEntry.GetTraceID() != "" {
+		t.Fatalf("expected no trace ID, got %q", panicEntry.GetTraceID())
+	}
+
+	if err := json.Unmarshal([]byte(panicEntry.GetContext()), &fields); err != nil || fields[contextEventName] != "invoice.failed" {
+		t.Fatalf("expected the event name: %s", panicEntry.GetContext())
+	}
+}
+
+func Test_Exporter_Shutdown(t *testing.T) {
+	store := storetest.NewStore(t, logstore.NewStoreOptions{LogTableName: "log_otel"})
+	exporter := NewExporter(store)
+
+	if err := exporter.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	records := []sdklog.Record{{}}
+	if err := exporter.Export(context.Background(), records); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := store.LogCount(context.Background(), logstore.LogQuery())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 0 {
+		t.Fatalf("expected no logs after shutdown, got %d", count)
+	}
+}
+
+func Test_LevelOf(t *testing.T) {
+	cases := []struct {
+		severity log.Severity
+		text     string
+		expected string
+	}{
+		{log.SeverityTrace2, "", logstore.LEVEL_TRACE},
+		{log.SeverityDebug, "", logstore.LEVEL_DEBUG},
+		{log.SeverityInfo4, "", logstore.LEVEL_INFO},
+		{log.SeverityWarn, "", logstore.LEVEL_WARNING},
+		{log.SeverityError3, "", logstore.LEVEL_ERROR},
+		{log.SeverityFatal, "", logstore.LEVEL_FATAL},
+		{log.SeverityFatal, "PANIC", logstore.LEVEL_PANIC},
+		{log.SeverityUndefined, "WARN", logstore.LEVEL_WARNING},
+		{log.SeverityUndefined, "", logstore.LEVEL_INFO},
+	}
+
+	for _, c := range cases {
+		if actual := levelOf(c.severity, c.text); actual != c.expected {
+			t.Errorf("expected %s for %v %q, got %s", c.expected, c.severity, c.text, actual)
+		}
+	}
+}
@@ -0,0 +1,458 @@
+package otelstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dracory/logstore"
+	"go.opentelemetry.io/otel/log"
+)
+
+// defaultOTLPBatchSize is the default number of entries sent per request
+const defaultOTLPBatchSize = 500
+
+// defaultScopeName is the instrumentation scope of entries without one
+const defaultScopeName = "github.com/dracory/logstore"
+
+// OTLPExporterOptions define the options for creating an OTLP exporter
+type OTLPExporterOptions struct {
+	// Store is the log store to read from
+	Store logstore.StoreInterface
+
+	// Endpoint is the OTLP/HTTP logs URL of the collector, i.e.
+	// "http://localhost:4318/v1/logs"
+	Endpoint string
+
+	// Headers are added to every request, i.e. an API key of the collector
+	Headers map[string]string
+
+	// Client sends the requests, by default a client with a 30 second timeout
+	Client *http.Client
+
+	// BatchSize is the number of entries sent per request, by default 500
+	BatchSize int
+}
+
+// OTLPExporter sends the stored entries to an OpenTelemetry collector, as
+// OTLP/HTTP JSON
+type OTLPExporter struct {
+	store     logstore.StoreInterface
+	endpoint  string
+	headers   map[string]string
+	client    *http.Client
+	batchSize int
+}
+
+// NewOTLPExporter creates a new OTLP exporter
+func NewOTLPExporter(opts OTLPExporterOptions) (*OTLPExporter, error) {
+	if opts.Store == nil {
+		return nil, errors.New("otlp exporter: Store is required")
+	}
+
+	if opts.Endpoint == "" {
+		return nil, errors.New("otlp exporter: Endpoint is required")
+	}
+
+	exporter := &OTLPExporter{
+		store:     opts.Store,
+		endpoint:  opts.Endpoint,
+		headers:   opts.Headers,
+		client:    opts.Client,
+		batchSize: opts.BatchSize,
+	}
+
+	if exporter.client == nil {
+		exporter.client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	if exporter.batchSize <= 0 {
+		exporter.batchSize = defaultOTLPBatchSize
+	}
+
+	return exporter, nil
+}
+
+// Export sends the entries matching the query, oldest first, one request
+// per batch, and returns the number of entries accepted by the collector.
+// Export stops at the first failed request; as entries are sent in time
+// order, a failed export can be resumed from the time of the last entry
+// accepted.
+//
+// Entries map to OTLP log records as follows:
+//   - the level as the severity number and text, and the message as the body
+//   - the trace and span IDs, when they are valid OpenTelemetry IDs
+//   - the service name, version, host name, environment and instance ID as
+//     resource attributes
+//   - the context fields, stack, request ID, tenant ID and entry ID as
+//     record attributes
+//
+// The "otel." context keys written by Exporter are restored as the
+// instrumentation scope, event name and resource, so records round-trip.
+func (e *OTLPExporter) Export(ctx context.Context, query logstore.LogQueryInterface) (int, error) {
+	exported := 0
+
+	err := e.store.LogForEachBatch(ctx, query, e.batchSize, func(batch []logstore.LogInterface) error {
+		records := make([]logstore.LogRecord, 0, len(batch))
+		for _, logEntry := range batch {
+			records = append(records, logstore.NewLogRecord(logEntry))
+		}
+
+		accepted, err := e.send(ctx, records)
+		exported += accepted
+		return err
+	})
+
+	return exported, err
+}
+
+// send posts the records to the collector, and returns the number of
+// records accepted
+func (e *OTLPExporter) send(ctx context.Context, records []logstore.LogRecord) (int, error) {
+	body, err := json.Marshal(otlpRequestOf(records))
+	if err != nil {
+		return 0, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range e.headers {
+		request.Header.Set(key, value)
+	}
+
+	response, err := e.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(io.LimitReader(response.Body, 64<<10))
+	if err != nil {
+		return 0, err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return 0, fmt.Errorf("otlp exporter: collector responded %s: %s", response.Status, strings.TrimSpace(string(responseBody)))
+	}
+
+	// a partial success reports the records the collector rejected
+	var result struct {
+		PartialSuccess struct {
+			RejectedLogRecords json.RawMessage `json:"rejectedLogRecords"`
+			ErrorMessage       string          `json:"errorMessage"`
+		} `json:"partialSuccess"`
+	}
+
+	if len(responseBody) == 0 || json.Unmarshal(responseBody, &result) != nil {
+		return len(records), nil
+	}
+
+	rejected, _ := strconv.Atoi(strings.Trim(string(result.PartialSuccess.RejectedLogRecords), `"`))
+	if rejected <= 0 {
+		return len(records), nil
+	}
+
+	return len(records) - rejected, fmt.Errorf("otlp exporter: collector rejected %d of %d log records: %s", rejected, len(records), result.PartialSuccess.ErrorMessage)
+}
+
+// == OTLP JSON ===============================================================
+
+type otlpRequest struct {
+	ResourceLogs []*otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource     `json:"resource"`
+	ScopeLogs []*otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber,omitempty"`
+	SeverityText         string         `json:"severityText,omitempty"`
+	EventName            string         `json:"eventName,omitempty"`
+	Body                 otlpAnyValue   `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+	TraceID              string         `json:"traceId,omitempty"`
+	SpanID               string         `json:"spanId,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+// otlpAnyValue holds one of its values, or none for a null value. Integers
+// are encoded as strings, as required by OTLP JSON.
+type otlpAnyValue struct {
+	StringValue *string        `json:"stringValue,omitempty"`
+	BoolValue   *bool          `json:"boolValue,omitempty"`
+	IntValue    *string        `json:"intValue,omitempty"`
+	DoubleValue *float64       `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArray     `json:"arrayValue,omitempty"`
+	KvlistValue *otlpKeyValues `json:"kvlistValue,omitempty"`
+}
+
+type otlpArray struct {
+	Values []otlpAnyValue `json:"values"`
+}
+
+type otlpKeyValues struct {
+	Values []otlpKeyValue `json:"values"`
+}
+
+// otlpRequestOf groups the records by resource and scope, keeping their order
+func otlpRequestOf(records []logstore.LogRecord) otlpRequest {
+	request := otlpRequest{ResourceLogs: []*otlpResourceLogs{}}
+	resources := map[string]*otlpResourceLogs{}
+	scopes := map[string]*otlpScopeLogs{}
+
+	for _, record := range records {
+		fields := contextFields(record.Context)
+
+		resource := otlpResource{Attributes: resourceAttributes(record, fields)}
+		resourceKey, _ := json.Marshal(resource)
+
+		resourceLogs, ok := resources[string(resourceKey)]
+		if !ok {
+			resourceLogs = &otlpResourceLogs{Resource: resource, ScopeLogs: []*otlpScopeLogs{}}
+			resources[string(resourceKey)] = resourceLogs
+			request.ResourceLogs = append(request.ResourceLogs, resourceLogs)
+		}
+
+		scope := otlpScope{Name: defaultScopeName}
+		if name, ok := fields[contextScopeName].(string); ok {
+			scope.Name = name
+			scope.Version, _ = fields[contextScopeVersion].(string)
+		}
+		delete(fields, contextScopeName)
+		delete(fields, contextScopeVersion)
+
+		scopeKey := string(resourceKey) + "\x00" + scope.Name + "\x00" + scope.Version
+
+		scopeLogs, ok := scopes[scopeKey]
+		if !ok {
+			scopeLogs = &otlpScopeLogs{Scope: scope, LogRecords: []otlpLogRecord{}}
+			scopes[scopeKey] = scopeLogs
+			resourceLogs.ScopeLogs = append(resourceLogs.ScopeLogs, scopeLogs)
+		}
+
+		scopeLogs.LogRecords = append(scopeLogs.LogRecords, otlpLogRecordOf(record, fields))
+	}
+
+	return request
+}
+
+// otlpLogRecordOf converts an entry to an OTLP log record. The fields are
+// the context fields left once the resource and scope are taken out.
+func otlpLogRecordOf(record logstore.LogRecord, fields map[string]any) otlpLogRecord {
+	timeUnixNano := strconv.FormatInt(record.Time.UnixNano(), 10)
+
+	logRecord := otlpLogRecord{
+		TimeUnixNano:         timeUnixNano,
+		ObservedTimeUnixNano: timeUnixNano,
+		SeverityNumber:       severityNumberOf(record.Level),
+		SeverityText:         record.Level,
+		Body:                 otlpValueOf(record.Message),
+	}
+
+	if eventName, ok := fields[contextEventName].(string); ok {
+		logRecord.EventName = eventName
+		delete(fields, contextEventName)
+	}
+
+	if isHexID(record.TraceID, 16) {
+		logRecord.TraceID = strings.ToLower(record.TraceID)
+	} else if record.TraceID != "" {
+		fields["trace_id"] = record.TraceID
+	}
+
+	if isHexID(record.SpanID, 8) {
+		logRecord.SpanID = strings.ToLower(record.SpanID)
+	} else if record.SpanID != "" {
+		fields["span_id"] = record.SpanID
+	}
+
+	if record.Stack != "" {
+		fields[attributeStack] = record.Stack
+	}
+
+	if record.RequestID != "" {
+		fields[attributeRequestID] = record.RequestID
+	}
+
+	if record.TenantID != "" {
+		fields["tenant_id"] = record.TenantID
+	}
+
+	if record.Occurrences > 1 {
+		fields["occurrences"] = record.Occurrences
+	}
+
+	fields["log.record.uid"] = record.ID
+
+	logRecord.Attributes = otlpKeyValuesOf(fields)
+	return logRecord
+}
+
+// resourceAttributes returns the resource attributes of the entry, taking
+// the "otel.resource" fields out of the context fields
+func resourceAttributes(record logstore.LogRecord, fields map[string]any) []otlpKeyValue {
+	attributes := map[string]any{}
+
+	if resource, ok := fields[contextResource].(map[string]any); ok {
+		for key, value := range resource {
+			attributes[key] = value
+		}
+		delete(fields, contextResource)
+	}
+
+	columns := map[string]string{
+		attributeServiceName:    record.ServiceName,
+		attributeServiceVersion: record.ServiceVersion,
+		attributeHostName:       record.HostName,
+		attributeEnvironment:    record.Environment,
+		attributeInstanceID:     record.InstanceID,
+	}
+
+	for key, value := range columns {
+		if value != "" {
+			attributes[key] = value
+		}
+	}
+
+	return otlpKeyValuesOf(attributes)
+}
+
+// contextFields decodes the context of an entry. A context which is not a
+// JSON object is kept as the "context" field.
+func contextFields(context string) map[string]any {
+	fields := map[string]any{}
+	if context == "" {
+		return fields
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(context))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&fields); err != nil {
+		return map[string]any{"context": context}
+	}
+
+	return fields
+}
+
+// otlpKeyValuesOf converts the fields to key values, sorted by key
+func otlpKeyValuesOf(fields map[string]any) []otlpKeyValue {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	keyValues := make([]otlpKeyValue, 0, len(keys))
+	for _, key := range keys {
+		keyValues = append(keyValues, otlpKeyValue{Key: key, Value: otlpValueOf(fields[key])})
+	}
+
+	return keyValues
+}
+
+// otlpValueOf converts a decoded JSON value to an OTLP value
+func otlpValueOf(value any) otlpAnyValue {
+	switch v := value.(type) {
+	case string:
+		return otlpAnyValue{StringValue: &v}
+	case bool:
+		return otlpAnyValue{BoolValue: &v}
+	case int:
+		s := strconv.Itoa(v)
+		return otlpAnyValue{IntValue: &s}
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			s := v.String()
+			return otlpAnyValue{IntValue: &s}
+		}
+		if f, err := v.Float64(); err == nil {
+			return otlpAnyValue{DoubleValue: &f}
+		}
+		s := v.String()
+		return otlpAnyValue{StringValue: &s}
+	case []any:
+		values := make([]otlpAnyValue, 0, len(v))
+		for _, item := range v {
+			values = append(values, otlpValueOf(item))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArray{Values: values}}
+	case map[string]any:
+		return otlpAnyValue{KvlistValue: &otlpKeyValues{Values: otlpKeyValuesOf(v)}}
+	case nil:
+		return otlpAnyValue{}
+	}
+
+	s := fmt.Sprint(value)
+	return otlpAnyValue{StringValue: &s}
+}
+
+// severityNumberOf maps a log level to the first OpenTelemetry severity
+// number of its range
+func severityNumberOf(level string) int {
+	switch level {
+	case logstore.LEVEL_TRACE:
+		return int(log.SeverityTrace1)
+	case logstore.LEVEL_DEBUG:
+		return int(log.SeverityDebug1)
+	case logstore.LEVEL_INFO:
+		return int(log.SeverityInfo1)
+	case logstore.LEVEL_WARNING:
+		return int(log.SeverityWarn1)
+	case logstore.LEVEL_ERROR:
+		return int(log.SeverityError1)
+	case logstore.LEVEL_FATAL, logstore.LEVEL_PANIC:
+		return int(log.SeverityFatal1)
+	}
+
+	return 0
+}
+
+// isHexID reports whether the ID is a non-zero hex ID of the given bytes
+func isHexID(id string, size int) bool {
+	if len(id) != size*2 {
+		return false
+	}
+
+	decoded, err := hex.DecodeString(id)
+	if err != nil {
+		return false
+	}
+
+	return slices.ContainsFunc(decoded, func(b byte) bool { return b != 0 })
+}
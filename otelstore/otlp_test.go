@@ -0,0 +1,271 @@
+package otelstore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dracory/logstore"
+	"github.com/dracory/logstore/internal/storetest"
+)
+
+// collector is an OTLP/HTTP collector stub recording the requests
+type collector struct {
+	mutex    sync.Mutex
+	requests []map[string]any
+	headers  []http.Header
+	status   int
+	response string
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	request := map[string]any{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.requests = append(c.requests, request)
+	c.headers = append(c.headers, r.Header.Clone())
+
+	if c.status != 0 {
+		w.WriteHeader(c.status)
+	}
+
+	w.Write([]byte(c.response))
+}
+
+// logRecords returns the log records of every request, with the resource
+// and scope of each record under "resource" and "scope"
+func (c *collector) logRecords() []map[string]any {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	records := []map[string]any{}
+
+	for _, request := range c.requests {
+		for _, resourceLogs := range request["resourceLogs"].([]any) {
+			resourceLogs := resourceLogs.(map[string]any)
+
+			for _, scopeLogs := range resourceLogs["scopeLogs"].([]any) {
+				scopeLogs := scopeLogs.(map[string]any)
+
+				for _, logRecord := range scopeLogs["logRecords"].([]any) {
+					logRecord := logRecord.(map[string]any)
+					logRecord["resource"] = attributesOf(resourceLogs["resource"])
+					logRecord["scope"] = scopeLogs["scope"]
+					logRecord["attributes"] = attributesOf(logRecord)
+					records = append(records, logRecord)
+				}
+			}
+		}
+	}
+
+	return records
+}
+
+// attributesOf returns the attributes of an OTLP object as a map of their
+// OTLP values
+func attributesOf(object any) map[string]any {
+	attributes := map[string]any{}
+
+	list, _ := object.(map[string]any)["attributes"].([]any)
+	for _, item := range list {
+		keyValue := item.(map[string]any)
+		attributes[keyValue["key"].(string)] = keyValue["value"]
+	}
+
+	return attributes
+}
+
+func Test_OTLPExporter(t *testing.T) {
+	store := storetest.NewStore(t, logstore.NewStoreOptions{LogTableName: "log_otel"})
+	emitRecords(t, store)
+
+	err := store.Log(logstore.NewLog().
+		SetLevel(logstore.LEVEL_ERROR).
+		SetMessage("payment declined").
+		SetTime(time.Date(2026, 10, 19, 14, 0, 2, 0, time.UTC)).
+		SetContext(`{"amount":12.5,"tags":["card"],"attempts":3}`).
+		SetTraceID("not-an-otel-id").
+		SetServiceName("payments"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stub := &collector{}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	exporter, err := NewOTLPExporter(OTLPExporterOptions{
+		Store:     store,
+		Endpoint:  server.URL + "/v1/logs",
+		Headers:   map[string]string{"X-Api-Key": "secret"},
+		BatchSize: 2,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exported, err := exporter.Export(context.Background(), logstore.LogQuery())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exported != 3 || len(stub.requests) != 2 {
+		t.Fatalf("expected 3 records in 2 requests, got %d in %d", exported, len(stub.requests))
+	}
+
+	if stub.headers[0].Get("X-Api-Key") != "secret" || stub.headers[0].Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected headers: %v", stub.headers[0])
+	}
+
+	records := stub.logRecords()
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+
+	warning := records[0]
+
+	if warning["severityNumber"] != float64(13) || warning["severityText"] != logstore.LEVEL_WARNING {
+		t.Fatalf("unexpected severity: %v", warning)
+	}
+
+	if warning["timeUnixNano"] != "1792418400000000000" {
+		t.Fatalf("unexpected time: %v", warning["timeUnixNano"])
+	}
+
+	if warning["traceId"] != "4bf92f3577b34da6a3ce929d0e0e4736" || warning["spanId"] != "00f067aa0ba902b7" {
+		t.Fatalf("unexpected trace and span IDs: %v", warning)
+	}
+
+	if body, _ := warning["body"].(map[string]any); body["stringValue"] != "invoice overdue" {
+		t.Fatalf("unexpected body: %v", warning["body"])
+	}
+
+	attributes := warning["attributes"].(map[string]any)
+	for key, expected := range map[string]string{
+		"invoice":              `{"stringValue":"inv-1"}`,
+		"days":                 `{"intValue":"12"}`,
+		"customer":             `{"kvlistValue":{"values":[{"key":"id","value":{"stringValue":"c-1"}}]}}`,
+		"request_id":           `{"stringValue":"req-1"}`,
+		"exception.stacktrace": `{"stringValue":"main.main()"}`,
+	} {
+		if actual, _ := json.Marshal(attributes[key]); string(actual) != expected {
+			t.Fatalf("expected %s for %s, got %s", expected, key, actual)
+		}
+	}
+
+	if _, ok := attributes["log.record.uid"]; !ok {
+		t.Fatalf("expected the entry ID: %v", attributes)
+	}
+
+	if _, ok := attributes[contextScopeName]; ok {
+		t.Fatalf("expected the scope to be taken out of the attributes: %v", attributes)
+	}
+
+	if scope := warning["scope"].(map[string]any); scope["name"] != "billing/invoices" || scope["version"] != "0.3.0" {
+		t.Fatalf("unexpected scope: %v", scope)
+	}
+
+	resource := warning["resource"].(map[string]any)
+	for key, expected := range map[string]string{
+		"service.name":                "billing",
+		"service.version":             "1.4.2",
+		"deployment.environment.name": "production",
+		"cloud.region":                "eu-west-1",
+	} {
+		if value, _ := resource[key].(map[string]any); value["stringValue"] != expected {
+			t.Fatalf("expected %s for %s: %v", expected, key, resource)
+		}
+	}
+
+	panicRecord := records[1]
+	if panicRecord["severityNumber"] != float64(21) || panicRecord["severityText"] != logstore.LEVEL_PANIC || panicRecord["eventName"] != "invoice.failed" {
+		t.Fatalf("unexpected panic record: %v", panicRecord)
+	}
+
+	declined := records[2]
+
+	if _, ok := declined["traceId"]; ok {
+		t.Fatalf("expected an invalid trace ID to be sent as an attribute: %v", declined)
+	}
+
+	attributes = declined["attributes"].(map[string]any)
+	for key, expected := range map[string]string{
+		"amount":   `{"doubleValue":12.5}`,
+		"attempts": `{"intValue":"3"}`,
+		"tags":     `{"arrayValue":{"values":[{"stringValue":"card"}]}}`,
+		"trace_id": `{"stringValue":"not-an-otel-id"}`,
+	} {
+		if actual, _ := json.Marshal(attributes[key]); string(actual) != expected {
+			t.Fatalf("expected %s for %s, got %s", expected, key, actual)
+		}
+	}
+
+	if scope := declined["scope"].(map[string]any); scope["name"] != defaultScopeName {
+		t.Fatalf("expected the default scope: %v", scope)
+	}
+
+	if value, _ := declined["resource"].(map[string]any)["service.name"].(map[string]any); value["stringValue"] != "payments" {
+		t.Fatalf("unexpected resource: %v", declined["resource"])
+	}
+}
+
+func Test_OTLPExporter_Errors(t *testing.T) {
+	if _, err := NewOTLPExporter(OTLPExporterOptions{Endpoint: "http://localhost:4318/v1/logs"}); err == nil {
+		t.Fatal("expected an error without a store")
+	}
+
+	store := storetest.NewStore(t, logstore.NewStoreOptions{LogTableName: "log_otel"})
+
+	if _, err := NewOTLPExporter(OTLPExporterOptions{Store: store}); err == nil {
+		t.Fatal("expected an error without an endpoint")
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := store.Info("entry"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stub := &collector{status: http.StatusServiceUnavailable, response: "overloaded"}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	exporter, err := NewOTLPExporter(OTLPExporterOptions{Store: store, Endpoint: server.URL, BatchSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exported, err := exporter.Export(context.Background(), logstore.LogQuery())
+	if err == nil || !strings.Contains(err.Error(), "503") || !strings.Contains(err.Error(), "overloaded") {
+		t.Fatalf("expected the collector error, got %v", err)
+	}
+
+	if exported != 0 || len(stub.requests) != 1 {
+		t.Fatalf("expected the export to stop at the first failure, got %d exported in %d requests", exported, len(stub.requests))
+	}
+
+	stub.status = 0
+	stub.response = `{"partialSuccess":{"rejectedLogRecords":"1","errorMessage":"too old"}}`
+
+	exported, err = exporter.Export(context.Background(), logstore.LogQuery())
+	if err == nil || !strings.Contains(err.Error(), "too old") {
+		t.Fatalf("expected the partial success error, got %v", err)
+	}
+
+	if exported != 0 {
+		t.Fatalf("expected no accepted records, got %d", exported)
+	}
+}
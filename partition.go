@@ -0,0 +1,410 @@
+package logstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Partitioning defines how entries are split across tables by their time
+type Partitioning int
+
+const (
+	// PartitioningNone stores all entries in the log table. This is the default.
+	PartitioningNone Partitioning = iota
+
+	// PartitioningDaily stores the entries of each day in a table of
+	// their own, i.e. "log_2026_10_19"
+	PartitioningDaily
+
+	// PartitioningWeekly stores the entries of each ISO week in a table
+	// of their own, i.e. "log_2026_w42"
+	PartitioningWeekly
+
+	// PartitioningMonthly stores the entries of each month in a table of
+	// their own, i.e. "log_2026_10"
+	PartitioningMonthly
+)
+
+// start returns the start of the period containing t
+func (p Partitioning) start(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch p {
+	case PartitioningDaily:
+		return day
+	case PartitioningWeekly:
+		// ISO weeks start on Monday
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case PartitioningMonthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	return time.Time{}
+}
+
+// next returns the start of the period following the one starting at start
+func (p Partitioning) next(start time.Time) time.Time {
+	switch p {
+	case PartitioningDaily:
+		return start.AddDate(0, 0, 1)
+	case PartitioningWeekly:
+		return start.AddDate(0, 0, 7)
+	case PartitioningMonthly:
+		return start.AddDate(0, 1, 0)
+	}
+
+	return start
+}
+
+// suffix names the period starting at start
+func (p Partitioning) suffix(start time.Time) string {
+	switch p {
+	case PartitioningDaily:
+		return start.Format("2006_01_02")
+	case PartitioningWeekly:
+		year, week := start.ISOWeek()
+		return fmt.Sprintf("%04d_w%02d", year, week)
+	case PartitioningMonthly:
+		return start.Format("2006_01")
+	}
+
+	return ""
+}
+
+// parseSuffix returns the start of the period named by suffix
+func (p Partitioning) parseSuffix(suffix string) (time.Time, bool) {
+	var start time.Time
+
+	switch p {
+	case PartitioningDaily:
+		t, err := time.Parse("2006_01_02", suffix)
+		if err != nil {
+			return time.Time{}, false
+		}
+		start = t
+	case PartitioningWeekly:
+		var year, week int
+		if _, err := fmt.Sscanf(suffix, "%04d_w%02d", &year, &week); err != nil {
+			return time.Time{}, false
+		}
+		// January 4th is always in the first ISO week
+		start = p.start(time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)).AddDate(0, 0, (week-1)*7)
+	case PartitioningMonthly:
+		t, err := time.Parse("2006_01", suffix)
+		if err != nil {
+			return time.Time{}, false
+		}
+		start = t
+	default:
+		return time.Time{}, false
+	}
+
+	// reject names which only look like partitions, i.e. "2026_w99"
+	if p.suffix(start) != suffix {
+		return time.Time{}, false
+	}
+
+	return start, true
+}
+
+// partition is one table of a partitioned store, holding the entries
+// logged from start (inclusive) to end (exclusive)
+type partition struct {
+	table string
+	start time.Time
+	end   time.Time
+}
+
+// partitionFor returns the partition holding the entries logged at t
+func (st *storeImplementation) partitionFor(t time.Time) partition {
+	start := st.partitioning.start(t)
+
+	return partition{
+		table: st.logTableName + "_" + st.partitioning.suffix(start),
+		start: start,
+		end:   st.partitioning.next(start),
+	}
+}
+
+// partitions lists the existing partitions, oldest first
+func (st *storeImplementation) partitions() ([]partition, error) {
+	tables, err := st.db.Schema().GetTables()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := st.logTableName + "_"
+	partitions := []partition{}
+
+	for _, table := range tables {
+		if !strings.HasPrefix(table.Name, prefix) {
+			continue
+		}
+
+		start, ok := st.partitioning.parseSuffix(strings.TrimPrefix(table.Name, prefix))
+		if !ok {
+			continue
+		}
+
+		partitions = append(partitions, partition{
+			table: table.Name,
+			start: start,
+			end:   st.partitioning.next(start),
+		})
+	}
+
+	sort.Slice(partitions, func(i, j int) bool {
+		return partitions[i].start.Before(partitions[j].start)
+	})
+
+	return partitions, nil
+}
+
+// partitionsFor lists the existing partitions overlapping the time range
+// of the query, oldest first
+func (st *storeImplementation) partitionsFor(query LogQueryInterface) ([]partition, error) {
+	partitions, err := st.partitions()
+	if err != nil {
+		return nil, err
+	}
+
+	if query == nil {
+		return partitions, nil
+	}
+
+	var from, to time.Time
+
+	if query.IsTimeGteSet() {
+		from = toTime(query.GetTimeGte())
+	}
+
+	if query.IsTimeLteSet() {
+		to = toTime(query.GetTimeLte())
+	}
+
+	overlapping := []partition{}
+	for _, p := range partitions {
+		if !from.IsZero() && !p.end.After(from) {
+			continue
+		}
+
+		if !to.IsZero() && p.start.After(to) {
+			continue
+		}
+
+		overlapping = append(overlapping, p)
+	}
+
+	return overlapping, nil
+}
+
+// partitionView returns a view of the store reading and writing a single
+// partition table
+func (st *storeImplementation) partitionView(table string) *storeImplementation {
+	child := *st
+	child.logTableName = table
+	child.partitioning = PartitioningNone
+	return &child
+}
+
+// ensurePartition creates the partition table, once per store
+func (st *storeImplementation) ensurePartition(ctx context.Context, p partition) error {
+	if _, loaded := st.migratedTables.LoadOrStore(p.table, true); loaded {
+		return nil
+	}
+
+	if err := st.partitionView(p.table).MigrateUp(ctx); err != nil {
+		st.migratedTables.Delete(p.table)
+		return err
+	}
+
+	return nil
+}
+
+// migratePartitions creates the current partition and the ones ahead of it
+func (st *storeImplementation) migratePartitions(ctx context.Context) error {
+	p := st.partitionFor(time.Now())
+
+	for range st.partitionsAhead + 1 {
+		if err := st.partitionView(p.table).MigrateUp(ctx); err != nil {
+			return err
+		}
+
+		st.migratedTables.Store(p.table, true)
+		p = st.partitionFor(p.end)
+	}
+
+	return nil
+}
+
+// forEachPartition calls fn with a view of each existing partition
+// overlapping the time range of the query, oldest first
+func (st *storeImplementation) forEachPartition(query LogQueryInterface, fn func(view *storeImplementation) error) error {
+	partitions, err := st.partitionsFor(query)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range partitions {
+		if err := fn(st.partitionView(p.table)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// partitionedLogList lists the logs of all partitions overlapping the time
+// range of the query. Each partition is queried for the first offset+limit
+// rows, which are merged, ordered and paged in memory.
+func (st *storeImplementation) partitionedLogList(query LogQueryInterface) ([]map[string]any, error) {
+	limit := 0
+	if query.IsLimitSet() && query.GetLimit() > 0 {
+		limit = query.GetLimit()
+		if query.IsOffsetSet() && query.GetOffset() > 0 {
+			limit += query.GetOffset()
+		}
+	}
+
+	results := []map[string]any{}
+
+	err := st.forEachPartition(query, func(view *storeImplementation) error {
+		q := view.orderQuery(view.buildFilterQuery(query), query)
+		if limit > 0 {
+			q = q.Limit(limit)
+		}
+
+		var rows []map[string]any
+		if err := q.Get(&rows); err != nil {
+			return err
+		}
+
+		results = append(results, rows...)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if query.IsOrderBySet() && query.GetOrderBy() != "" {
+		column := query.GetOrderBy()
+		descending := !query.IsOrderDirectionSet() || query.GetOrderDirection() == "" ||
+			strings.EqualFold(query.GetOrderDirection(), "desc")
+
+		sort.SliceStable(results, func(i, j int) bool {
+			if descending {
+				return compareValues(results[i][column], results[j][column]) > 0
+			}
+			return compareValues(results[i][column], results[j][column]) < 0
+		})
+	}
+
+	if query.IsOffsetSet() && query.GetOffset() > 0 {
+		if query.GetOffset() >= len(results) {
+			return []map[string]any{}, nil
+		}
+		results = results[query.GetOffset():]
+	}
+
+	if query.IsLimitSet() && query.GetLimit() > 0 && query.GetLimit() < len(results) {
+		results = results[:query.GetLimit()]
+	}
+
+	return results, nil
+}
+
+// partitionedLogCount counts the logs of all partitions overlapping the
+// time range of the query
+func (st *storeImplementation) partitionedLogCount(query LogQueryInterface) (int64, error) {
+	var total int64
+
+	err := st.forEachPartition(query, func(view *storeImplementation) error {
+		var count int64
+		if err := view.buildFilterQuery(query).Count(&count); err != nil {
+			return err
+		}
+
+		total += count
+		return nil
+	})
+
+	return total, err
+}
+
+// PartitionList lists the partition tables, oldest first. It is empty
+// when partitioning is not enabled.
+func (st *storeImplementation) PartitionList(ctx context.Context) ([]string, error) {
+	if st.partitioning == PartitioningNone {
+		return []string{}, nil
+	}
+
+	partitions, err := st.partitions()
+	if err != nil {
+		return []string{}, err
+	}
+
+	tables := make([]string, 0, len(partitions))
+	for _, p := range partitions {
+		tables = append(tables, p.table)
+	}
+
+	return tables, nil
+}
+
+// PartitionDropBefore drops the partition tables holding only entries
+// logged before the given time, and returns their names. This is the
+// retention mechanism of a partitioned store.
+func (st *storeImplementation) PartitionDropBefore(ctx context.Context, before time.Time) ([]string, error) {
+	if st.partitioning == PartitioningNone {
+		return []string{}, errors.New("log store: partitioning is not enabled")
+	}
+
+	if st.isSharedTenantView() {
+		return []string{}, errors.New("log store: partitions shared by tenants cannot be dropped from a tenant view")
+	}
+
+	partitions, err := st.partitions()
+	if err != nil {
+		return []string{}, err
+	}
+
+	dropped := []string{}
+	for _, p := range partitions {
+		if p.end.After(before) {
+			continue
+		}
+
+		if err := st.db.Schema().Drop(p.table); err != nil {
+			return dropped, err
+		}
+
+		st.migratedTables.Delete(p.table)
+		dropped = append(dropped, p.table)
+	}
+
+	return dropped, nil
+}
+
+// dropPartitions drops all partition tables
+func (st *storeImplementation) dropPartitions() error {
+	partitions, err := st.partitions()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range partitions {
+		if err := st.db.Schema().Drop(p.table); err != nil {
+			return err
+		}
+
+		st.migratedTables.Delete(p.table)
+	}
+
+	return nil
+}
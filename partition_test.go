@@ -0,0 +1,160 @@
+package logstore
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func Test_Partitioning_Suffix(t *testing.T) {
+	at := time.Date(2026, time.October, 19, 15, 4, 5, 0, time.UTC)
+
+	cases := map[Partitioning]string{
+		PartitioningDaily:   "2026_10_19",
+		PartitioningWeekly:  "2026_w43",
+		PartitioningMonthly: "2026_10",
+	}
+
+	for partitioning, expected := range cases {
+		start := partitioning.start(at)
+
+		if got := partitioning.suffix(start); got != expected {
+			t.Fatalf("expected suffix %q, got %q", expected, got)
+		}
+
+		parsed, ok := partitioning.parseSuffix(expected)
+		if !ok || !parsed.Equal(start) {
+			t.Fatalf("expected %q to parse to %v, got %v", expected, start, parsed)
+		}
+
+		if !partitioning.next(start).After(at) {
+			t.Fatalf("expected the partition of %v to end after it", at)
+		}
+	}
+
+	if _, ok := PartitioningWeekly.parseSuffix("2026_w99"); ok {
+		t.Fatal("expected an invalid week not to parse")
+	}
+
+	if _, ok := PartitioningMonthly.parseSuffix("acme"); ok {
+		t.Fatal("expected a tenant suffix not to parse")
+	}
+}
+
+func Test_Store_Partitioning(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_partitioned",
+		AutomigrateEnabled: true,
+		Partitioning:       PartitioningMonthly,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	current := PartitioningMonthly.suffix(PartitioningMonthly.start(now))
+	next := PartitioningMonthly.suffix(PartitioningMonthly.next(PartitioningMonthly.start(now)))
+
+	partitions, err := s.PartitionList(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error from PartitionList: %v", err)
+	}
+
+	if !slices.Equal(partitions, []string{"log_partitioned_" + current, "log_partitioned_" + next}) {
+		t.Fatalf("expected MigrateUp to create the current and next partitions, got %v", partitions)
+	}
+
+	january := time.Date(2025, time.January, 15, 10, 0, 0, 0, time.UTC)
+	february := time.Date(2025, time.February, 15, 10, 0, 0, 0, time.UTC)
+
+	entries := []LogInterface{
+		NewLog().SetLevel(LEVEL_ERROR).SetMessage("order 1 failed").SetTime(january),
+		NewLog().SetLevel(LEVEL_INFO).SetMessage("january").SetTime(january.Add(time.Hour)),
+		NewLog().SetLevel(LEVEL_ERROR).SetMessage("order 2 failed").SetTime(february),
+		NewLog().SetLevel(LEVEL_INFO).SetMessage("february").SetTime(february.Add(time.Hour)),
+	}
+
+	for _, entry := range entries {
+		if err := s.LogCreate(ctx, entry); err != nil {
+			t.Fatalf("unexpected error from LogCreate: %v", err)
+		}
+	}
+
+	partitions, err = s.PartitionList(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error from PartitionList: %v", err)
+	}
+
+	if len(partitions) != 4 || partitions[0] != "log_partitioned_2025_01" || partitions[1] != "log_partitioned_2025_02" {
+		t.Fatalf("expected partitions to be created on write, got %v", partitions)
+	}
+
+	count, err := s.LogCount(ctx, LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogCount: %v", err)
+	}
+
+	if count != 4 {
+		t.Fatalf("expected 4 logs across partitions, got %d", count)
+	}
+
+	logs, err := s.LogList(ctx, LogQuery().SetOrderBy(COLUMN_TIME).SetOrderDirection("desc").SetOffset(1).SetLimit(2))
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	if len(logs) != 2 || logs[0].GetMessage() != "order 2 failed" || logs[1].GetMessage() != "january" {
+		t.Fatalf("expected logs to be ordered and paged across partitions, got %v", logs)
+	}
+
+	logs, err = s.LogList(ctx, LogQuery().SetTimeGte("2025-02-01 00:00:00"))
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 logs from the february partition, got %d", len(logs))
+	}
+
+	issues, err := s.IssueList(ctx, LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from IssueList: %v", err)
+	}
+
+	if len(issues) != 1 || issues[0].Count != 2 || !issues[0].FirstSeen.Equal(january) {
+		t.Fatalf("expected 1 issue merged across partitions, got %v", issues)
+	}
+
+	if err := s.LogDeleteByID(ctx, entries[3].GetID()); err != nil {
+		t.Fatalf("unexpected error from LogDeleteByID: %v", err)
+	}
+
+	if found, _ := s.LogFindByID(ctx, entries[3].GetID()); found != nil {
+		t.Fatal("expected the log to be deleted from its partition")
+	}
+
+	dropped, err := s.PartitionDropBefore(ctx, time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error from PartitionDropBefore: %v", err)
+	}
+
+	if !slices.Equal(dropped, []string{"log_partitioned_2025_01"}) {
+		t.Fatalf("expected only the january partition to be dropped, got %v", dropped)
+	}
+
+	count, err = s.LogCount(ctx, LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogCount: %v", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("expected 1 log left, got %d", count)
+	}
+}
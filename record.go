@@ -0,0 +1,73 @@
+package logstore
+
+import "time"
+
+// LogRecord is the JSON representation of a log entry, used by archives,
+// exports and imports. Field names match the column names.
+type LogRecord struct {
+	ID             string    `json:"id"`
+	Level          string    `json:"level"`
+	Message        string    `json:"message"`
+	Context        string    `json:"context,omitempty"`
+	Time           time.Time `json:"time"`
+	Occurrences    int       `json:"occurrences,omitempty"`
+	LastSeen       time.Time `json:"last_seen,omitzero"`
+	Fingerprint    string    `json:"fingerprint,omitempty"`
+	Stack          string    `json:"stack,omitempty"`
+	TraceID        string    `json:"trace_id,omitempty"`
+	SpanID         string    `json:"span_id,omitempty"`
+	RequestID      string    `json:"request_id,omitempty"`
+	ServiceName    string    `json:"service_name,omitempty"`
+	ServiceVersion string    `json:"service_version,omitempty"`
+	HostName       string    `json:"host_name,omitempty"`
+	Environment    string    `json:"environment,omitempty"`
+	InstanceID     string    `json:"instance_id,omitempty"`
+	TenantID       string    `json:"tenant_id,omitempty"`
+}
+
+// NewLogRecord converts a log entry to its JSON representation
+func NewLogRecord(logEntry LogInterface) LogRecord {
+	return LogRecord{
+		ID:             logEntry.GetID(),
+		Level:          logEntry.GetLevel(),
+		Message:        logEntry.GetMessage(),
+		Context:        logEntry.GetContext(),
+		Time:           logEntry.GetTime(),
+		Occurrences:    logEntry.GetOccurrences(),
+		LastSeen:       logEntry.GetLastSeen(),
+		Fingerprint:    logEntry.GetFingerprint(),
+		Stack:          logEntry.GetStack(),
+		TraceID:        logEntry.GetTraceID(),
+		SpanID:         logEntry.GetSpanID(),
+		RequestID:      logEntry.GetRequestID(),
+		ServiceName:    logEntry.GetServiceName(),
+		ServiceVersion: logEntry.GetServiceVersion(),
+		HostName:       logEntry.GetHostName(),
+		Environment:    logEntry.GetEnvironment(),
+		InstanceID:     logEntry.GetInstanceID(),
+		TenantID:       logEntry.GetTenantID(),
+	}
+}
+
+// ToLog converts the record back to a log entry
+func (r LogRecord) ToLog() LogInterface {
+	logEntry := NewLogWithData(r.ID, r.Level, r.Message, r.Context, r.Time)
+
+	if r.Occurrences > 0 {
+		logEntry.SetOccurrences(r.Occurrences)
+	}
+
+	return logEntry.
+		SetLastSeen(r.LastSeen).
+		SetFingerprint(r.Fingerprint).
+		SetStack(r.Stack).
+		SetTraceID(r.TraceID).
+		SetSpanID(r.SpanID).
+		SetRequestID(r.RequestID).
+		SetServiceName(r.ServiceName).
+		SetServiceVersion(r.ServiceVersion).
+		SetHostName(r.HostName).
+		SetEnvironment(r.Environment).
+		SetInstanceID(r.InstanceID).
+		SetTenantID(r.TenantID)
+}
@@ -0,0 +1,91 @@
+package logstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// HTTPRecoveryMiddleware returns a middleware recovering the panics of the
+// handler. Unlike PanicWithContext, it does not panic again: the panic is
+// added as a panic entry, with the panic value, stack trace and request
+// details, and the client receives a 500 Internal Server Error when no
+// response was written yet.
+//
+// http.ErrAbortHandler is not recovered, so net/http aborts the response
+// as usual.
+func HTTPRecoveryMiddleware(store StoreInterface) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			defer func() {
+				value := recover()
+				if value == nil {
+					return
+				}
+
+				if err, ok := value.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(value)
+				}
+
+				fields := map[string]any{
+					"method":     r.Method,
+					"path":       r.URL.Path,
+					"remote_ip":  remoteIP(r, false),
+					"user_agent": r.UserAgent(),
+				}
+
+				if requestID := CorrelationIDsFromContext(r.Context()).RequestID; requestID != "" {
+					fields["request_id"] = requestID
+				}
+
+				logPanic(r.Context(), store, value, panicStack(), fields)
+
+				if !recorder.wroteHeader {
+					http.Error(recorder, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+		})
+	}
+}
+
+// Go runs fn in a new goroutine, recovering its panic, which is added as a
+// panic entry with the panic value and stack trace, so the process keeps
+// running.
+func Go(store StoreInterface, fn func()) {
+	go func() {
+		defer func() {
+			if value := recover(); value != nil {
+				logPanic(context.Background(), store, value, panicStack(), nil)
+			}
+		}()
+
+		fn()
+	}()
+}
+
+// logPanic adds a panic entry for the recovered value
+func logPanic(ctx context.Context, store StoreInterface, value any, stack string, fields map[string]any) {
+	if fields == nil {
+		fields = map[string]any{}
+	}
+
+	fields["panic"] = fmt.Sprint(value)
+	fields["panic_type"] = fmt.Sprintf("%T", value)
+
+	logEntry := NewLog().
+		SetLevel(LEVEL_PANIC).
+		SetMessage(fmt.Sprintf("panic: %v", value)).
+		SetStack(stack)
+
+	if contextBytes, err := json.Marshal(fields); err == nil {
+		logEntry.SetContext(string(contextBytes))
+	}
+
+	store.LogCtx(ctx, logEntry)
+}
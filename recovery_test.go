@@ -0,0 +1,137 @@
+package logstore
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_HTTPRecoveryMiddleware(t *testing.T) {
+	s, err := NewStore(NewStoreOptions{
+		DB:                 InitDB(),
+		LogTableName:       "log_recovery",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	handler := HTTPRecoveryMiddleware(s)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("order not found")
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+	request = request.WithContext(ContextWithCorrelationIDs(request.Context(), CorrelationIDs{RequestID: "req-1"}))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, request)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", w.Code)
+	}
+
+	logs, err := s.LogList(context.Background(), LogQuery().SetLevel(LEVEL_PANIC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(logs) != 1 {
+		t.Fatalf("expected 1 panic log, got %d", len(logs))
+	}
+
+	logEntry := logs[0]
+
+	if logEntry.GetMessage() != "panic: order not found" || logEntry.GetRequestID() != "req-1" {
+		t.Fatalf("unexpected log %q %q", logEntry.GetMessage(), logEntry.GetRequestID())
+	}
+
+	if !strings.Contains(logEntry.GetContext(), `"path":"/orders/1"`) || !strings.Contains(logEntry.GetContext(), `"panic":"order not found"`) {
+		t.Fatalf("expected the request details and panic value: %s", logEntry.GetContext())
+	}
+
+	if !strings.HasPrefix(logEntry.GetStack(), "github.com/dracory/logstore.Test_HTTPRecoveryMiddleware.func1\n") {
+		t.Fatalf("expected the stack to start at the panicking handler: %s", logEntry.GetStack())
+	}
+}
+
+func Test_HTTPRecoveryMiddleware_AfterWrite(t *testing.T) {
+	s, err := NewStore(NewStoreOptions{
+		DB:                 InitDB(),
+		LogTableName:       "log_recovery_after_write",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	handler := HTTPRecoveryMiddleware(s)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		panic("late failure")
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected the status written before the panic, got %d", w.Code)
+	}
+
+	defer func() {
+		if value := recover(); value != http.ErrAbortHandler {
+			t.Fatalf("expected http.ErrAbortHandler to be panicked again, got %v", value)
+		}
+	}()
+
+	HTTPRecoveryMiddleware(s)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func Test_Go(t *testing.T) {
+	db := InitDB()
+	db.SetMaxOpenConns(1)
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_go",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	Go(s, func() {
+		var orders map[string]int
+		orders["o-1"]++ // assignment to a nil map
+	})
+
+	var logs []LogInterface
+	deadline := time.Now().Add(2 * time.Second)
+
+	for len(logs) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+
+		logs, err = s.LogList(context.Background(), LogQuery().SetLevel(LEVEL_PANIC))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(logs) != 1 {
+		t.Fatal("expected the panic to be logged")
+	}
+
+	if !strings.Contains(logs[0].GetMessage(), "assignment to entry in nil map") {
+		t.Fatalf("unexpected message %q", logs[0].GetMessage())
+	}
+
+	if !strings.HasPrefix(logs[0].GetStack(), "github.com/dracory/logstore.Test_Go.func1\n") {
+		t.Fatalf("expected the stack to start at the panicking function: %s", logs[0].GetStack())
+	}
+}
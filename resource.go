@@ -0,0 +1,44 @@
+package logstore
+
+// Resource describes the service writing log entries, so several services
+// can share one log table
+type Resource struct {
+	// ServiceName is the name of the service, i.e. "billing"
+	ServiceName string
+
+	// ServiceVersion is the version of the service, i.e. "1.4.2"
+	ServiceVersion string
+
+	// HostName is the host the service runs on, detected with os.Hostname when empty
+	HostName string
+
+	// Environment is the deployment environment, i.e. "production"
+	Environment string
+
+	// InstanceID identifies the service instance, i.e. a pod name
+	InstanceID string
+}
+
+// applyResource stamps the resource attributes on the entry, keeping any
+// the entry already has (i.e. when importing entries of another service)
+func (st *storeImplementation) applyResource(logEntry LogInterface) {
+	if logEntry.GetServiceName() == "" {
+		logEntry.SetServiceName(st.resource.ServiceName)
+	}
+
+	if logEntry.GetServiceVersion() == "" {
+		logEntry.SetServiceVersion(st.resource.ServiceVersion)
+	}
+
+	if logEntry.GetHostName() == "" {
+		logEntry.SetHostName(st.resource.HostName)
+	}
+
+	if logEntry.GetEnvironment() == "" {
+		logEntry.SetEnvironment(st.resource.Environment)
+	}
+
+	if logEntry.GetInstanceID() == "" {
+		logEntry.SetInstanceID(st.resource.InstanceID)
+	}
+}
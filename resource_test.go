@@ -0,0 +1,61 @@
+package logstore
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func Test_Store_Resource(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_resource",
+		AutomigrateEnabled: true,
+		Resource: Resource{
+			ServiceName:    "billing",
+			ServiceVersion: "1.4.2",
+			Environment:    "production",
+			InstanceID:     "billing-0",
+		},
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+
+	if err := s.Info("invoice sent"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := s.LogCreate(ctx, NewLog().SetMessage("imported").SetServiceName("shipping")); err != nil {
+		t.Fatalf("unexpected error from LogCreate: %v", err)
+	}
+
+	logs, err := s.LogList(ctx, LogQuery().SetServiceName("billing").SetEnvironment("production"))
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	if len(logs) != 1 {
+		t.Fatalf("expected 1 billing log, got %d", len(logs))
+	}
+
+	hostName, _ := os.Hostname()
+
+	if logs[0].GetServiceVersion() != "1.4.2" || logs[0].GetInstanceID() != "billing-0" || logs[0].GetHostName() != hostName {
+		t.Fatalf("unexpected resource: %s %s %s", logs[0].GetServiceVersion(), logs[0].GetInstanceID(), logs[0].GetHostName())
+	}
+
+	count, err := s.LogCount(ctx, LogQuery().SetServiceName("shipping").SetHostName(hostName))
+	if err != nil {
+		t.Fatalf("unexpected error from LogCount: %v", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("expected the explicit service name to be kept, got %d shipping logs", count)
+	}
+}
@@ -3,6 +3,7 @@ package logstore
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
@@ -16,6 +17,8 @@ type SlogHandler struct {
 	buffer      *bytes.Buffer
 	mutex       *sync.Mutex
 	logStore    StoreInterface
+	attrs       []slog.Attr
+	groupPrefix string
 }
 
 func NewSlogHandler(logStore StoreInterface) *SlogHandler {
@@ -33,47 +36,56 @@ func (handler *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool
 	return handler.slogHandler.Enabled(ctx, level)
 }
 
+// Handle stores the record, taking its correlation IDs from ctx
 func (handler *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
-	level := record.Level.String()
-	message := record.Message
 	attrs, err := handler.computeAttrs(ctx, record)
 
 	if err != nil {
 		return fmt.Errorf("error when calling computeAttrs: %w", err)
 	}
 
-	if level == slog.LevelDebug.String() {
-		return handler.logStore.DebugWithContext(message, attrs)
-	}
-
-	if level == slog.LevelInfo.String() {
-		return handler.logStore.InfoWithContext(message, attrs)
+	contextBytes, err := json.Marshal(attrs)
+	if err != nil {
+		contextBytes = []byte("JSON encode error")
 	}
 
-	if level == slog.LevelWarn.String() {
-		return handler.logStore.WarnWithContext(message, attrs)
-	}
+	logEntry := NewLog().
+		SetLevel(slogLevelToLevel(record.Level)).
+		SetMessage(record.Message).
+		SetContext(string(contextBytes))
 
-	if level == slog.LevelError.String() {
-		return handler.logStore.ErrorWithContext(message, attrs)
+	if !record.Time.IsZero() {
+		logEntry.SetTime(record.Time.UTC())
 	}
 
-	return handler.logStore.FatalWithContext(message, attrs)
+	return handler.logStore.LogCtx(ctx, logEntry)
 }
 
 func (handler *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return &SlogHandler{
-		slogHandler: handler.slogHandler.WithAttrs(attrs),
-		buffer:      handler.buffer,
-		mutex:       handler.mutex,
+	child := handler.clone()
+	child.slogHandler = handler.slogHandler.WithAttrs(attrs)
+	for _, attr := range attrs {
+		attr.Key = handler.groupPrefix + attr.Key
+		child.attrs = append(child.attrs, attr)
 	}
+	return child
 }
 
 func (handler *SlogHandler) WithGroup(name string) slog.Handler {
+	child := handler.clone()
+	child.slogHandler = handler.slogHandler.WithGroup(name)
+	child.groupPrefix = handler.groupPrefix + name + "."
+	return child
+}
+
+func (handler *SlogHandler) clone() *SlogHandler {
 	return &SlogHandler{
-		slogHandler: handler.slogHandler.WithGroup(name),
+		slogHandler: handler.slogHandler,
 		buffer:      handler.buffer,
 		mutex:       handler.mutex,
+		logStore:    handler.logStore,
+		attrs:       append([]slog.Attr{}, handler.attrs...),
+		groupPrefix: handler.groupPrefix,
 	}
 }
 
@@ -94,10 +106,33 @@ func (handler *SlogHandler) computeAttrs(
 
 	attrs := map[string]any{}
 
-	r.Attrs(func(attr slog.Attr) bool {
+	for _, attr := range handler.attrs {
 		attrs[attr.Key] = attr.Value.Any()
+	}
+
+	r.Attrs(func(attr slog.Attr) bool {
+		attrs[handler.groupPrefix+attr.Key] = attr.Value.Any()
 		return true
 	})
 
 	return attrs, nil
 }
+
+// slogLevelToLevel maps a slog level to a log level. Levels above error
+// are stored as fatal, without exiting like FatalWithContext would.
+func slogLevelToLevel(level slog.Level) string {
+	switch {
+	case level < slog.LevelDebug:
+		return LEVEL_TRACE
+	case level < slog.LevelInfo:
+		return LEVEL_DEBUG
+	case level < slog.LevelWarn:
+		return LEVEL_INFO
+	case level < slog.LevelError:
+		return LEVEL_WARNING
+	case level == slog.LevelError:
+		return LEVEL_ERROR
+	default:
+		return LEVEL_FATAL
+	}
+}
@@ -0,0 +1,99 @@
+package logstore
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func Test_SlogHandler_LevelMapping(t *testing.T) {
+	code := replaceOsExit(t)
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_slog_levels",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	handler := NewSlogHandler(s)
+	logger := slog.New(handler)
+	ctx := context.Background()
+
+	// below debug is not enabled, so call the handler directly
+	record := slog.NewRecord(time.Now(), slog.LevelDebug-4, "trace", 0)
+	if err := handler.Handle(ctx, record); err != nil {
+		t.Fatalf("unexpected error from Handle: %v", err)
+	}
+
+	logger.Debug("debug")
+	logger.Info("info", "name", "John Doe")
+	logger.Warn("warn")
+	logger.Error("error")
+	logger.Log(ctx, slog.LevelError+4, "fatal")
+
+	if *code != -1 {
+		t.Fatalf("expected the slog handler never to exit, got status %d", *code)
+	}
+
+	expected := map[string]string{
+		"trace": LEVEL_TRACE,
+		"debug": LEVEL_DEBUG,
+		"info":  LEVEL_INFO,
+		"warn":  LEVEL_WARNING,
+		"error": LEVEL_ERROR,
+		"fatal": LEVEL_FATAL,
+	}
+
+	logs, err := s.LogList(ctx, LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	if len(logs) != len(expected) {
+		t.Fatalf("expected %d logs, got %d", len(expected), len(logs))
+	}
+
+	for _, log := range logs {
+		if log.GetLevel() != expected[log.GetMessage()] {
+			t.Fatalf("expected %q to be stored as %q, got %q", log.GetMessage(), expected[log.GetMessage()], log.GetLevel())
+		}
+	}
+}
+
+func Test_SlogHandler_ContextAndAttrs(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_slog_context",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	logger := slog.New(NewSlogHandler(s)).With("service", "api").WithGroup("http")
+	ctx := ContextWithCorrelationIDs(context.Background(), CorrelationIDs{TraceID: "trace-1"})
+
+	logger.InfoContext(ctx, "request", "status", 200)
+
+	logs, err := s.LogList(ctx, LogQuery().SetTraceID("trace-1"))
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	if len(logs) != 1 {
+		t.Fatalf("expected 1 log with the trace ID, got %d", len(logs))
+	}
+
+	if logs[0].GetContext() != `{"http.status":200,"service":"api"}` {
+		t.Fatalf("unexpected context %s", logs[0].GetContext())
+	}
+}
@@ -0,0 +1,220 @@
+package logstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultSSEHeartbeat is the default interval between heartbeats
+const defaultSSEHeartbeat = 15 * time.Second
+
+// SSEHandlerOptions define the options of NewSSEHandler
+type SSEHandlerOptions struct {
+	// Store is the log store streamed, required
+	Store StoreInterface
+
+	// Authorize is called before every request, which is refused with
+	// 403 Forbidden when it returns false. By default every request is allowed.
+	Authorize func(r *http.Request) bool
+
+	// Heartbeat is the interval between the comments sent to keep idle
+	// connections open, by default 15 seconds
+	Heartbeat time.Duration
+
+	// Subscribe are the options of the subscription of each client. Set
+	// PollInterval to also stream the entries written by other processes.
+	Subscribe SubscribeOptions
+}
+
+// SSEHandler streams new log entries as server-sent events
+type SSEHandler struct {
+	store   *storeImplementation
+	options SSEHandlerOptions
+}
+
+// NewSSEHandler returns a handler streaming the entries created while the
+// client is connected as server-sent events, filtered by the query
+// parameters of NewHTTPHandler. Each "log" event carries the entry as JSON,
+// with an ID made of the entry time and ID.
+//
+// A client reconnecting with the Last-Event-ID header, or the
+// last_event_id query parameter, first receives the entries logged since
+// the time of that event. Entries sharing its second may be received again,
+// so clients should skip the IDs they have seen.
+func NewSSEHandler(opts SSEHandlerOptions) (*SSEHandler, error) {
+	if opts.Store == nil {
+		return nil, errors.New("sse handler: Store is required")
+	}
+
+	store, ok := opts.Store.(*storeImplementation)
+	if !ok {
+		return nil, errors.New("sse handler: Store must be created with NewStore")
+	}
+
+	if opts.Heartbeat <= 0 {
+		opts.Heartbeat = defaultSSEHeartbeat
+	}
+
+	return &SSEHandler{store: store, options: opts}, nil
+}
+
+func (h *SSEHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.options.Authorize != nil && !h.options.Authorize(r) {
+		writeHTTPError(w, http.StatusForbidden, errors.New("forbidden"))
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeHTTPError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+
+	query, err := logQueryFromValues(r.URL.Query())
+	if err != nil {
+		writeHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	var resumeTime time.Time
+	var resumeID string
+
+	if lastEventID != "" {
+		resumeTime, resumeID, err = parseSSEEventID(lastEventID)
+		if err != nil {
+			writeHTTPError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// subscribing before replaying, so no entry logged meanwhile is missed
+	logs, err := h.store.Subscribe(ctx, query, h.options.Subscribe)
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	controller := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if err := controller.Flush(); err != nil {
+		return
+	}
+
+	// the replayed IDs, skipped when also delivered live, until live entries
+	// are past the second of the newest replayed entry
+	var replayed map[string]bool
+	var replayedUntil time.Time
+
+	if !resumeTime.IsZero() {
+		replayed = map[string]bool{}
+
+		err := h.store.forEachLogBatchSince(ctx, query, resumeTime, 0, func(batch []LogInterface) error {
+			for _, logEntry := range batch {
+				if logEntry.GetID() == resumeID {
+					continue
+				}
+
+				replayed[logEntry.GetID()] = true
+				if logEntry.GetTime().After(replayedUntil) {
+					replayedUntil = logEntry.GetTime()
+				}
+
+				if err := writeSSEEvent(w, logEntry); err != nil {
+					return err
+				}
+			}
+
+			return controller.Flush()
+		})
+
+		if err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(h.options.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case logEntry, ok := <-logs:
+			if !ok {
+				return
+			}
+
+			if replayed != nil {
+				if replayed[logEntry.GetID()] {
+					continue
+				}
+
+				if logEntry.GetTime().Truncate(time.Second).After(replayedUntil.Truncate(time.Second)) {
+					replayed = nil
+				}
+			}
+
+			if err := writeSSEEvent(w, logEntry); err != nil {
+				return
+			}
+		}
+
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeSSEEvent writes the entry as a "log" event
+func writeSSEEvent(w http.ResponseWriter, logEntry LogInterface) error {
+	data, err := json.Marshal(NewLogRecord(logEntry))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: log\ndata: %s\n\n", sseEventID(logEntry), data)
+	return err
+}
+
+// sseEventID identifies the event of an entry by its time and ID
+func sseEventID(logEntry LogInterface) string {
+	return logEntry.GetTime().UTC().Format(time.RFC3339) + "/" + logEntry.GetID()
+}
+
+// parseSSEEventID returns the time and entry ID of an event ID
+func parseSSEEventID(eventID string) (time.Time, string, error) {
+	value, id, ok := strings.Cut(eventID, "/")
+	if !ok {
+		return time.Time{}, "", fmt.Errorf("invalid Last-Event-ID %q", eventID)
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("invalid Last-Event-ID %q", eventID)
+	}
+
+	return t, id, nil
+}
@@ -0,0 +1,180 @@
+package logstore
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sseEvent is an event read by readSSEEvent
+type sseEvent struct {
+	id     string
+	record LogRecord
+}
+
+// readSSEEvent reads the next event, skipping comments
+func readSSEEvent(t *testing.T, reader *bufio.Reader) sseEvent {
+	t.Helper()
+
+	event := sseEvent{}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("unexpected error reading the stream: %v", err)
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "" && event.id != "":
+			return event
+		case strings.HasPrefix(line, "id: "):
+			event.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event.record); err != nil {
+				t.Fatalf("invalid event data: %v", err)
+			}
+		}
+	}
+}
+
+// subscriptionCount returns the number of in-process subscriptions
+func subscriptionCount(s StoreInterface) int {
+	registry := s.(*storeImplementation).subscriptions
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	return len(registry.subscriptions)
+}
+
+func Test_SSEHandler(t *testing.T) {
+	db := InitDB()
+	db.SetMaxOpenConns(1)
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_sse",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	handler, err := NewSSEHandler(SSEHandlerOptions{Store: s, Heartbeat: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	connect := func(ctx context.Context, lastEventID string) *bufio.Reader {
+		t.Helper()
+
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"?level=error", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if lastEventID != "" {
+			request.Header.Set("Last-Event-ID", lastEventID)
+		}
+
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if response.Header.Get("Content-Type") != "text/event-stream" {
+			t.Fatalf("unexpected content type %q", response.Header.Get("Content-Type"))
+		}
+
+		return bufio.NewReader(response.Body)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := connect(ctx, "")
+
+	if err := s.Info("ignored"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Error("first failure"); err != nil {
+		t.Fatal(err)
+	}
+
+	first := readSSEEvent(t, stream)
+	if first.record.Message != "first failure" || !strings.HasSuffix(first.id, "/"+first.record.ID) {
+		t.Fatalf("unexpected event: %+v", first)
+	}
+
+	// the client disconnects, and entries are logged meanwhile
+	cancel()
+
+	deadline := time.Now().Add(time.Second)
+	for subscriptionCount(s) > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if count := subscriptionCount(s); count != 0 {
+		t.Fatalf("expected the subscription to end with the client, got %d", count)
+	}
+
+	if err := s.Error("missed failure"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	stream = connect(ctx, first.id)
+
+	replayed := readSSEEvent(t, stream)
+	if replayed.record.Message != "missed failure" {
+		t.Fatalf("expected the missed entry to be replayed, got %+v", replayed)
+	}
+
+	if err := s.Error("live failure"); err != nil {
+		t.Fatal(err)
+	}
+
+	live := readSSEEvent(t, stream)
+	if live.record.Message != "live failure" {
+		t.Fatalf("expected the live entry, got %+v", live)
+	}
+}
+
+func Test_SSEHandler_InvalidLastEventID(t *testing.T) {
+	s, err := NewStore(NewStoreOptions{
+		DB:                 InitDB(),
+		LogTableName:       "log_sse_invalid",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	handler, err := NewSSEHandler(SSEHandlerOptions{Store: s})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Last-Event-ID", "yesterday")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, request)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+
+	if _, err := NewSSEHandler(SSEHandlerOptions{}); err == nil {
+		t.Fatal("expected an error without a store")
+	}
+}
@@ -0,0 +1,133 @@
+package logstore
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// stackMaxDepth is the maximum number of frames captured in a stack trace
+const stackMaxDepth = 64
+
+// stackSkipPrefixes lists the function name prefixes of the logging
+// machinery, which are trimmed from the top of captured stack traces. The
+// logging libraries of the integrations are included, as entries logged
+// through zap, logrus or zerolog are captured below their own frames.
+var stackSkipPrefixes = []string{
+	"github.com/dracory/logstore",
+	"log/slog.",
+	"log.",
+	"go.uber.org/zap.",
+	"go.uber.org/zap/",
+	"github.com/sirupsen/logrus.",
+	"github.com/rs/zerolog.",
+	"github.com/rs/zerolog/",
+}
+
+// noStackCaptureContextKey is the context key set by ContextWithoutStackCapture
+type noStackCaptureContextKey struct{}
+
+// ContextWithoutStackCapture returns a copy of ctx disabling stack capture
+// for the entries logged with it, i.e. entries bridged from another logging
+// system, for which the stack of the bridge would be meaningless
+func ContextWithoutStackCapture(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noStackCaptureContextKey{}, true)
+}
+
+// captureStack sets the stack trace of the entry, if its level is
+// configured for stack capture, it has none yet and ctx allows it
+func (st *storeImplementation) captureStack(ctx context.Context, logEntry LogInterface) {
+	if logEntry.GetStack() != "" || !slices.Contains(st.stackLevels, logEntry.GetLevel()) {
+		return
+	}
+
+	if ctx != nil && ctx.Value(noStackCaptureContextKey{}) != nil {
+		return
+	}
+
+	logEntry.SetStack(callerStack(2))
+}
+
+// callerStack returns the stack trace of the calling goroutine, formatted
+// as "function\n\tfile:line" per frame, starting at the first frame outside
+// the logging machinery
+func callerStack(skip int) string {
+	pcs := make([]uintptr, stackMaxDepth)
+	n := runtime.Callers(skip+1, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var builder strings.Builder
+	skipping := true
+
+	for {
+		frame, more := frames.Next()
+
+		if skipping && isLoggingFrame(frame.Function) {
+			if !more {
+				break
+			}
+			continue
+		}
+		skipping = false
+
+		writeFrame(&builder, frame)
+
+		if !more {
+			break
+		}
+	}
+
+	return builder.String()
+}
+
+// panicStack returns the stack trace of a panicking goroutine, when called
+// by a deferred function, starting at the frame which panicked
+func panicStack() string {
+	pcs := make([]uintptr, stackMaxDepth)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var builder strings.Builder
+	panicked := false
+
+	for {
+		frame, more := frames.Next()
+
+		switch {
+		case !panicked:
+			panicked = frame.Function == "runtime.gopanic"
+		case builder.Len() == 0 && strings.HasPrefix(frame.Function, "runtime."):
+			// runtime errors, i.e. runtime.sigpanic, panic from the runtime
+		default:
+			writeFrame(&builder, frame)
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return builder.String()
+}
+
+// writeFrame writes the frame as "function\n\tfile:line"
+func writeFrame(builder *strings.Builder, frame runtime.Frame) {
+	builder.WriteString(frame.Function)
+	builder.WriteString("\n\t")
+	builder.WriteString(frame.File)
+	builder.WriteString(":")
+	builder.WriteString(strconv.Itoa(frame.Line))
+	builder.WriteString("\n")
+}
+
+// isLoggingFrame returns whether the function belongs to the logging machinery
+func isLoggingFrame(function string) bool {
+	for _, prefix := range stackSkipPrefixes {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package logstore
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func Test_Store_StackTraceLevels(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_stack",
+		AutomigrateEnabled: true,
+		StackTraceLevels:   []string{LEVEL_ERROR},
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+
+	if err := s.Error("with stack"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := s.Info("without stack"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	errorLogs, err := s.LogList(ctx, LogQuery().SetLevel(LEVEL_ERROR))
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	if len(errorLogs) != 1 {
+		t.Fatalf("expected 1 error log, got %d", len(errorLogs))
+	}
+
+	stack := errorLogs[0].GetStack()
+	if !strings.Contains(stack, "testing.tRunner") {
+		t.Fatalf("expected the stack to contain the caller frames, got %q", stack)
+	}
+
+	if strings.Contains(stack, "storeImplementation") {
+		t.Fatalf("expected the logging frames to be trimmed, got %q", stack)
+	}
+
+	infoLogs, err := s.LogList(ctx, LogQuery().SetLevel(LEVEL_INFO))
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	if len(infoLogs) != 1 || infoLogs[0].GetStack() != "" {
+		t.Fatal("expected the info log to have no stack")
+	}
+
+	bridgedCtx := ContextWithoutStackCapture(ctx)
+
+	if err := s.LogCtx(bridgedCtx, NewLog().SetLevel(LEVEL_ERROR).SetMessage("bridged")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := s.LogCreateMany(bridgedCtx, []LogInterface{NewLog().SetLevel(LEVEL_ERROR).SetMessage("bridged")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	bridgedLogs, err := s.LogList(ctx, LogQuery().SetMessageContains("bridged"))
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	if len(bridgedLogs) != 2 || bridgedLogs[0].GetStack() != "" || bridgedLogs[1].GetStack() != "" {
+		t.Fatal("expected the logs of a context without stack capture to have no stack")
+	}
+}
+
+func Test_CallerStack_Format(t *testing.T) {
+	stack := callerStack(1)
+
+	lines := strings.Split(strings.TrimSpace(stack), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("expected at least one frame, got %q", stack)
+	}
+
+	if strings.HasPrefix(lines[0], "\t") || !strings.HasPrefix(lines[1], "\t") {
+		t.Fatalf("expected function and file lines, got %q", stack)
+	}
+}
+
+func Test_IsLoggingFrame(t *testing.T) {
+	frames := map[string]bool{
+		"github.com/dracory/logstore.(*storeImplementation).LogCtx": true,
+		"github.com/dracory/logstore/zapstore.(*Core).Write":        true,
+		"log/slog.(*Logger).log":                                    true,
+		"go.uber.org/zap.(*Logger).Error":                           true,
+		"go.uber.org/zap/zapcore.(*CheckedEntry).Write":             true,
+		"github.com/sirupsen/logrus.(*Entry).log":                   true,
+		"github.com/rs/zerolog.(*Event).msg":                        true,
+		"main.main":                                                 false,
+		"go.uber.org/zapper.Run":                                    false,
+		"github.com/sirupsen/logrusx.Run":                           false,
+		"github.com/acme/billing.(*Service).Charge":                 false,
+	}
+
+	for function, expected := range frames {
+		if actual := isLoggingFrame(function); actual != expected {
+			t.Errorf("expected %v for %s, got %v", expected, function, actual)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package logstore
+
+import (
+	"context"
+	"time"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+)
+
+// Stats summarises the logs matching a query
+type Stats struct {
+	// Count is the number of logs
+	Count int64 `json:"count"`
+
+	// CountByLevel is the number of logs of each level, levels without
+	// logs are left out
+	CountByLevel map[string]int64 `json:"count_by_level"`
+
+	// Oldest is the time of the oldest log
+	Oldest time.Time `json:"oldest,omitzero"`
+
+	// Newest is the time of the newest log
+	Newest time.Time `json:"newest,omitzero"`
+}
+
+const (
+	statsColumnCount  = "count"
+	statsColumnOldest = "oldest"
+	statsColumnNewest = "newest"
+)
+
+// LogStats counts the logs matching the query filters per level, any level
+// including custom ones, and finds the time of the oldest and newest ones,
+// with one grouped query. The limit, offset and order of the query are
+// ignored.
+func (st *storeImplementation) LogStats(ctx context.Context, query LogQueryInterface) (Stats, error) {
+	if query == nil {
+		query = LogQuery()
+	}
+
+	stats := Stats{CountByLevel: map[string]int64{}}
+
+	if err := query.Validate(); err != nil {
+		return stats, err
+	}
+
+	var results []map[string]any
+
+	if st.partitioning != PartitioningNone {
+		err := st.forEachPartition(query, func(view *storeImplementation) error {
+			var partitionResults []map[string]any
+			if err := view.statsQuery(query).Get(&partitionResults); err != nil {
+				return err
+			}
+			results = append(results, partitionResults...)
+			return nil
+		})
+
+		if err != nil {
+			return stats, err
+		}
+	} else if err := st.statsQuery(query).Get(&results); err != nil {
+		return stats, err
+	}
+
+	for _, result := range results {
+		count := toInt64(result[statsColumnCount])
+		if count == 0 {
+			continue
+		}
+
+		level, _ := result[COLUMN_LEVEL].(string)
+		stats.CountByLevel[level] += count
+		stats.Count += count
+
+		if oldest := toTime(result[statsColumnOldest]); !oldest.IsZero() && (stats.Oldest.IsZero() || oldest.Before(stats.Oldest)) {
+			stats.Oldest = oldest
+		}
+
+		if newest := toTime(result[statsColumnNewest]); newest.After(stats.Newest) {
+			stats.Newest = newest
+		}
+	}
+
+	return stats, nil
+}
+
+// statsQuery groups the logs of the store's table matching the query
+// filters by level
+func (st *storeImplementation) statsQuery(query LogQueryInterface) contractsorm.Query {
+	return st.buildFilterQuery(query).
+		Select(COLUMN_LEVEL + ", " +
+			"COUNT(*) AS " + statsColumnCount + ", " +
+			"MIN(" + COLUMN_TIME + ") AS " + statsColumnOldest + ", " +
+			"MAX(" + COLUMN_TIME + ") AS " + statsColumnNewest).
+		Group(COLUMN_LEVEL)
+}
@@ -0,0 +1,127 @@
+package logstore
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_Store_LogStats(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_stats",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+	at := time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)
+
+	entries := []LogInterface{
+		NewLog().SetLevel(LEVEL_INFO).SetMessage("a").SetTime(at),
+		NewLog().SetLevel(LEVEL_INFO).SetMessage("b").SetTime(at.Add(time.Hour)),
+		NewLog().SetLevel(LEVEL_ERROR).SetMessage("c").SetTime(at.Add(2 * time.Hour)),
+		NewLog().SetLevel("notice").SetMessage("d").SetTime(at.Add(3 * time.Hour)),
+	}
+
+	for _, entry := range entries {
+		if err := s.LogCreate(ctx, entry); err != nil {
+			t.Fatalf("unexpected error from LogCreate: %v", err)
+		}
+	}
+
+	stats, err := s.LogStats(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error from LogStats: %v", err)
+	}
+
+	if stats.Count != 4 || stats.CountByLevel[LEVEL_INFO] != 2 || stats.CountByLevel[LEVEL_ERROR] != 1 {
+		t.Fatalf("unexpected counts: %+v", stats)
+	}
+
+	if stats.CountByLevel["notice"] != 1 {
+		t.Fatalf("expected custom levels to be counted: %+v", stats)
+	}
+
+	if _, ok := stats.CountByLevel[LEVEL_DEBUG]; ok {
+		t.Fatal("expected levels without logs to be left out")
+	}
+
+	if !stats.Oldest.Equal(at) || !stats.Newest.Equal(at.Add(3*time.Hour)) {
+		t.Fatalf("unexpected oldest and newest times: %v %v", stats.Oldest, stats.Newest)
+	}
+
+	stats, err = s.LogStats(ctx, LogQuery().SetLevel(LEVEL_ERROR))
+	if err != nil {
+		t.Fatalf("unexpected error from LogStats: %v", err)
+	}
+
+	if stats.Count != 1 || len(stats.CountByLevel) != 1 || stats.CountByLevel[LEVEL_ERROR] != 1 {
+		t.Fatalf("expected the level filter to apply, got %+v", stats)
+	}
+
+	stats, err = s.LogStats(ctx, LogQuery().SetMessageContains("a").SetLimit(0))
+	if err != nil {
+		t.Fatalf("unexpected error from LogStats: %v", err)
+	}
+
+	if stats.Count != 1 {
+		t.Fatalf("expected the filter to apply, got %d", stats.Count)
+	}
+
+	stats, err = s.LogStats(ctx, LogQuery().SetMessageContains("nothing"))
+	if err != nil {
+		t.Fatalf("unexpected error from LogStats: %v", err)
+	}
+
+	if stats.Count != 0 || len(stats.CountByLevel) != 0 || !stats.Oldest.IsZero() {
+		t.Fatalf("expected empty stats, got %+v", stats)
+	}
+}
+
+func Test_Store_LogStats_Partitioned(t *testing.T) {
+	s, err := NewStore(NewStoreOptions{
+		DB:                 InitDB(),
+		LogTableName:       "log_stats_partitioned",
+		AutomigrateEnabled: true,
+		Partitioning:       PartitioningMonthly,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+	september := time.Date(2026, time.September, 30, 23, 0, 0, 0, time.UTC)
+	october := time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)
+
+	entries := []LogInterface{
+		NewLog().SetLevel(LEVEL_ERROR).SetMessage("a").SetTime(september),
+		NewLog().SetLevel(LEVEL_ERROR).SetMessage("b").SetTime(october),
+		NewLog().SetLevel(LEVEL_INFO).SetMessage("c").SetTime(october),
+	}
+
+	for _, entry := range entries {
+		if err := s.LogCreate(ctx, entry); err != nil {
+			t.Fatalf("unexpected error from LogCreate: %v", err)
+		}
+	}
+
+	stats, err := s.LogStats(ctx, LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogStats: %v", err)
+	}
+
+	if stats.Count != 3 || stats.CountByLevel[LEVEL_ERROR] != 2 || stats.CountByLevel[LEVEL_INFO] != 1 {
+		t.Fatalf("expected the counts of both partitions to be merged, got %+v", stats)
+	}
+
+	if !stats.Oldest.Equal(september) || !stats.Newest.Equal(october) {
+		t.Fatalf("unexpected oldest and newest times: %v %v", stats.Oldest, stats.Newest)
+	}
+}
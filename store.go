@@ -1,12 +1,18 @@
 package logstore
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/dracory/neat"
@@ -37,9 +43,37 @@ type StoreInterface interface {
 	// EnableDebug enables or disables debug mode
 	EnableDebug(debug bool)
 
+	// Flush writes any entries held back by deduplication
+	Flush(ctx context.Context) error
+
+	// With returns a child logger adding the fields to the context of every entry
+	With(fields map[string]any) StoreInterface
+
+	// Named returns a child logger adding the component name to the context of every entry
+	Named(component string) StoreInterface
+
+	// ForTenant returns a view which stamps every entry with the tenant ID
+	// and scopes every query and delete to the tenant
+	ForTenant(tenantID string) StoreInterface
+
+	// Archive streams the logs matching the query to w as compressed NDJSON
+	Archive(ctx context.Context, query LogQueryInterface, w io.Writer, opts ...ArchiveOptions) (ArchiveManifest, error)
+
+	// Export streams the logs matching the query to w as NDJSON, CSV or logfmt
+	Export(ctx context.Context, query LogQueryInterface, format ExportFormat, w io.Writer, opts ...ExportOptions) error
+
+	// PartitionList lists the partition tables, oldest first
+	PartitionList(ctx context.Context) ([]string, error)
+
+	// PartitionDropBefore drops the partition tables holding only entries logged before the given time
+	PartitionDropBefore(ctx context.Context, before time.Time) ([]string, error)
+
 	// Log adds a log entry
 	Log(logEntry LogInterface) error
 
+	// LogCtx adds a log entry, taking its correlation IDs from ctx
+	LogCtx(ctx context.Context, logEntry LogInterface) error
+
 	// Debug adds a debug log
 	Debug(message string) error
 
@@ -52,10 +86,14 @@ type StoreInterface interface {
 	// ErrorWithContext adds an error log with context data
 	ErrorWithContext(message string, context interface{}) error
 
-	// Fatal adds a fatal log
+	// ErrorErr adds an error log for err, recording each wrapped cause and
+	// its type, with optional key/value attributes
+	ErrorErr(err error, attrs ...any) error
+
+	// Fatal adds a fatal log, then acts on the configured FatalBehavior
 	Fatal(message string) error
 
-	// FatalWithContext adds a fatal log with context data
+	// FatalWithContext adds a fatal log with context data, then acts on the configured FatalBehavior
 	FatalWithContext(message string, context interface{}) error
 
 	// Info adds an info log
@@ -82,13 +120,49 @@ type StoreInterface interface {
 	// WarnWithContext adds a warn log with context data
 	WarnWithContext(message string, context interface{}) error
 
+	// Tracew adds a trace log with key/value pairs or Fields as context data
+	Tracew(message string, keysAndValues ...any) error
+
+	// Debugw adds a debug log with key/value pairs or Fields as context data
+	Debugw(message string, keysAndValues ...any) error
+
+	// Infow adds an info log with key/value pairs or Fields as context data
+	Infow(message string, keysAndValues ...any) error
+
+	// Warnw adds a warn log with key/value pairs or Fields as context data
+	Warnw(message string, keysAndValues ...any) error
+
+	// Errorw adds an error log with key/value pairs or Fields as context data
+	Errorw(message string, keysAndValues ...any) error
+
+	// Fatalw adds a fatal log with key/value pairs or Fields as context data,
+	// then acts on the configured FatalBehavior
+	Fatalw(message string, keysAndValues ...any) error
+
 	LogCount(ctx context.Context, query LogQueryInterface) (int64, error)
 	LogCreate(ctx context.Context, logEntry LogInterface) error
+	LogCreateMany(ctx context.Context, logEntries []LogInterface) error
 	LogList(ctx context.Context, query LogQueryInterface) ([]LogInterface, error)
+
+	// LogForEachBatch calls fn with the logs matching the query filters, in
+	// batches ordered by time and ID
+	LogForEachBatch(ctx context.Context, query LogQueryInterface, batchSize int, fn func(batch []LogInterface) error) error
+
 	LogDelete(ctx context.Context, logEntry LogInterface) error
 	LogDeleteByID(ctx context.Context, id string) error
 	LogDeleteByIDs(ctx context.Context, ids []string) error
 	LogFindByID(ctx context.Context, id string) (LogInterface, error)
+
+	// IssueList groups error, fatal and panic logs by fingerprint
+	IssueList(ctx context.Context, query LogQueryInterface) ([]Issue, error)
+
+	// LogStats counts the logs matching the query filters per level, and
+	// finds the time of the oldest and newest ones
+	LogStats(ctx context.Context, query LogQueryInterface) (Stats, error)
+
+	// Subscribe delivers the entries created after the call which match
+	// the query filters, until the context is done
+	Subscribe(ctx context.Context, query LogQueryInterface, opts ...SubscribeOptions) (<-chan LogInterface, error)
 }
 
 // == TYPE ====================================================================
@@ -97,11 +171,27 @@ var _ StoreInterface = (*storeImplementation)(nil)
 
 // storeImplementation implements StoreInterface for log operations.
 type storeImplementation struct {
-	logTableName       string
-	db                 *neat.Database
-	automigrateEnabled bool
-	debugEnabled       bool
-	logger             *slog.Logger
+	logTableName         string
+	db                   *neat.Database
+	automigrateEnabled   bool
+	debugEnabled         bool
+	logger               *slog.Logger
+	deduplicator         *deduplicator
+	stackLevels          []string
+	fatalBehavior        FatalBehavior
+	fatalHook            func(logEntry LogInterface)
+	boundFields          map[string]any
+	name                 string
+	correlationExtractor CorrelationExtractor
+	resource             Resource
+	tenantID             string
+	tenantScoped         bool
+	tenantTables         bool
+	tenantTableName      func(logTableName string, tenantID string) string
+	migratedTables       *sync.Map
+	partitioning         Partitioning
+	partitionsAhead      int
+	subscriptions        *subscriptionRegistry
 }
 
 // NewStoreOptions define the options for creating a new log store
@@ -110,6 +200,46 @@ type NewStoreOptions struct {
 	DB                 *sql.DB
 	AutomigrateEnabled bool
 	DebugEnabled       bool
+
+	// DeduplicationWindow, when greater than zero, collapses identical
+	// entries (same level, message and context keys) logged within the
+	// window into a single row carrying an occurrence count
+	DeduplicationWindow time.Duration
+
+	// StackTraceLevels lists the levels for which a stack trace is
+	// captured automatically, i.e. LEVEL_ERROR, LEVEL_FATAL, LEVEL_PANIC
+	StackTraceLevels []string
+
+	// FatalBehavior defines what Fatal and FatalWithContext do after
+	// logging, by default flush and exit the process with status 1
+	FatalBehavior FatalBehavior
+
+	// FatalHook is called with the fatal entry, when FatalBehavior is FatalBehaviorHook
+	FatalHook func(logEntry LogInterface)
+
+	// CorrelationExtractor returns the trace, span and request IDs carried
+	// by a context.Context, by default CorrelationIDsFromContext
+	CorrelationExtractor CorrelationExtractor
+
+	// Resource describes the service writing the entries, stamped on
+	// every entry. The host name is detected when left empty.
+	Resource Resource
+
+	// TenantTables stores the entries of each ForTenant view in a table of
+	// its own, created on first use, instead of the shared log table
+	TenantTables bool
+
+	// TenantTableName names the table of a tenant when TenantTables is
+	// set, by default DefaultTenantTableName
+	TenantTableName func(logTableName string, tenantID string) string
+
+	// Partitioning splits the entries across tables by their time, named
+	// after the log table and the period, i.e. "log_2026_10"
+	Partitioning Partitioning
+
+	// PartitionsAhead is the number of partitions MigrateUp creates ahead
+	// of the current one, by default 1
+	PartitionsAhead int
 }
 
 // NewStore creates a new log store
@@ -122,6 +252,10 @@ func NewStore(opts NewStoreOptions) (StoreInterface, error) {
 		return nil, errors.New("log store: logTableName is required")
 	}
 
+	if opts.FatalBehavior == FatalBehaviorHook && opts.FatalHook == nil {
+		return nil, errors.New("log store: FatalHook is required for FatalBehaviorHook")
+	}
+
 	neatDB, err := neat.NewFromSQLDB(opts.DB)
 	if err != nil {
 		return nil, err
@@ -129,11 +263,42 @@ func NewStore(opts NewStoreOptions) (StoreInterface, error) {
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	store := &storeImplementation{
-		logTableName:       opts.LogTableName,
-		db:                 neatDB,
-		automigrateEnabled: opts.AutomigrateEnabled,
-		debugEnabled:       opts.DebugEnabled,
-		logger:             logger,
+		logTableName:         opts.LogTableName,
+		db:                   neatDB,
+		automigrateEnabled:   opts.AutomigrateEnabled,
+		debugEnabled:         opts.DebugEnabled,
+		logger:               logger,
+		stackLevels:          opts.StackTraceLevels,
+		fatalBehavior:        opts.FatalBehavior,
+		fatalHook:            opts.FatalHook,
+		correlationExtractor: opts.CorrelationExtractor,
+		resource:             opts.Resource,
+		tenantTables:         opts.TenantTables,
+		tenantTableName:      opts.TenantTableName,
+		migratedTables:       &sync.Map{},
+		partitioning:         opts.Partitioning,
+		partitionsAhead:      opts.PartitionsAhead,
+		subscriptions:        newSubscriptionRegistry(),
+	}
+
+	if store.partitionsAhead <= 0 {
+		store.partitionsAhead = 1
+	}
+
+	if store.tenantTableName == nil {
+		store.tenantTableName = DefaultTenantTableName
+	}
+
+	if store.correlationExtractor == nil {
+		store.correlationExtractor = CorrelationIDsFromContext
+	}
+
+	if store.resource.HostName == "" {
+		store.resource.HostName, _ = os.Hostname()
+	}
+
+	if opts.DeduplicationWindow > 0 {
+		store.deduplicator = newDeduplicator(opts.DeduplicationWindow)
 	}
 
 	if store.automigrateEnabled {
@@ -160,20 +325,34 @@ func (st *storeImplementation) GetLogTableName() string {
 	return st.logTableName
 }
 
-// SetLogTableName sets the log table name
+// SetLogTableName sets the log table name. On a tenant view sharing the log table the name is left unchanged, so
+// the view cannot be pointed at the table of other tenants.
 func (st *storeImplementation) SetLogTableName(logTableName string) {
+	if st.isSharedTenantView() {
+		st.logger.Warn("SetLogTableName: the table of a tenant view sharing the log table cannot be changed",
+			"tenant_id", st.tenantID,
+			"table", st.logTableName,
+			"requested_table", logTableName)
+		return
+	}
+
 	st.logTableName = logTableName
 }
 
 // == MIGRATE =================================================================
 
-// MigrateUp creates the log table
+// MigrateUp creates the log table, or adds any missing columns to an existing one.
+// When partitioning is enabled it creates the current and upcoming partitions.
 func (st *storeImplementation) MigrateUp(ctx context.Context, tx ...*sql.Tx) error {
+	if st.partitioning != PartitioningNone {
+		return st.migratePartitions(ctx)
+	}
+
 	if st.db.Schema().HasTable(st.logTableName) {
 		if st.debugEnabled {
 			st.logger.Info("MigrateUp: table already exists", "table", st.logTableName)
 		}
-		return nil
+		return st.migrateColumns(st.logTableName)
 	}
 
 	err := st.db.Schema().Create(st.logTableName, func(table contractsschema.Blueprint) {
@@ -192,11 +371,109 @@ func (st *storeImplementation) MigrateUp(ctx context.Context, tx ...*sql.Tx) err
 		return err
 	}
 
+	return st.migrateColumns(st.logTableName)
+}
+
+// columnMigration describes a column added after the original schema
+type columnMigration struct {
+	column string
+	define func(table contractsschema.Blueprint)
+}
+
+// columnMigrations lists the columns added after the original schema, oldest first
+var columnMigrations = []columnMigration{
+	{COLUMN_OCCURRENCES, func(table contractsschema.Blueprint) {
+		table.Integer(COLUMN_OCCURRENCES).Default(1)
+	}},
+	{COLUMN_LAST_SEEN, func(table contractsschema.Blueprint) {
+		table.DateTime(COLUMN_LAST_SEEN).Nullable()
+	}},
+	{COLUMN_FINGERPRINT, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_FINGERPRINT, 64).Default("")
+		table.Index(COLUMN_FINGERPRINT)
+	}},
+	{COLUMN_STACK, func(table contractsschema.Blueprint) {
+		table.Text(COLUMN_STACK).Nullable()
+	}},
+	{COLUMN_TRACE_ID, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_TRACE_ID, 64).Default("")
+		table.Index(COLUMN_TRACE_ID)
+	}},
+	{COLUMN_SPAN_ID, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_SPAN_ID, 64).Default("")
+		table.Index(COLUMN_SPAN_ID)
+	}},
+	{COLUMN_REQUEST_ID, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_REQUEST_ID, 64).Default("")
+		table.Index(COLUMN_REQUEST_ID)
+	}},
+	{COLUMN_SERVICE_NAME, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_SERVICE_NAME, 100).Default("")
+		table.Index(COLUMN_SERVICE_NAME)
+	}},
+	{COLUMN_SERVICE_VERSION, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_SERVICE_VERSION, 100).Default("")
+		table.Index(COLUMN_SERVICE_VERSION)
+	}},
+	{COLUMN_HOST_NAME, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_HOST_NAME, 100).Default("")
+		table.Index(COLUMN_HOST_NAME)
+	}},
+	{COLUMN_ENVIRONMENT, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_ENVIRONMENT, 100).Default("")
+		table.Index(COLUMN_ENVIRONMENT)
+	}},
+	{COLUMN_INSTANCE_ID, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_INSTANCE_ID, 100).Default("")
+		table.Index(COLUMN_INSTANCE_ID)
+	}},
+	{COLUMN_TENANT_ID, func(table contractsschema.Blueprint) {
+		table.String(COLUMN_TENANT_ID, 100).Default("")
+		table.Index(COLUMN_TENANT_ID)
+	}},
+}
+
+// migrateColumns adds any missing columns from columnMigrations, so tables
+// created by older versions keep working
+func (st *storeImplementation) migrateColumns(tableName string) error {
+	missing := []columnMigration{}
+	for _, migration := range columnMigrations {
+		if !st.db.Schema().HasColumn(tableName, migration.column) {
+			missing = append(missing, migration)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	err := st.db.Schema().Table(tableName, func(table contractsschema.Blueprint) {
+		for _, migration := range missing {
+			migration.define(table)
+		}
+	})
+
+	if err != nil {
+		if st.debugEnabled {
+			st.logger.Error("MigrateUp: adding columns failed", "table", tableName, "error", err)
+		}
+		return err
+	}
+
 	return nil
 }
 
-// MigrateDown drops the log table
+// MigrateDown drops the log table, or all partitions when partitioning is
+// enabled. A tenant view sharing the log table cannot drop it.
 func (st *storeImplementation) MigrateDown(ctx context.Context, tx ...*sql.Tx) error {
+	if st.isSharedTenantView() {
+		return errors.New("log store: the log table shared by tenants cannot be dropped from a tenant view")
+	}
+
+	if st.partitioning != PartitioningNone {
+		return st.dropPartitions()
+	}
+
 	if !st.db.Schema().HasTable(st.logTableName) {
 		if st.debugEnabled {
 			st.logger.Info("MigrateDown: table does not exist", "table", st.logTableName)
@@ -233,15 +510,39 @@ func (st *storeImplementation) EnableDebug(debug bool) {
 	}
 }
 
+// == FLUSH ===================================================================
+
+// Flush writes any entries held back by deduplication.
+func (st *storeImplementation) Flush(ctx context.Context) error {
+	if st.deduplicator == nil {
+		return nil
+	}
+
+	return st.deduplicator.flush(ctx)
+}
+
 // == CONVENIENCE LOGGERS =====================================================
 
-// Log adds a log (shortcut for LogCreate)
+// Log adds a log (shortcut for LogCreate). When deduplication is enabled
+// the entry may be held back and collapsed with identical ones
 func (st *storeImplementation) Log(logEntry LogInterface) error {
+	return st.LogCtx(context.Background(), logEntry)
+}
+
+// LogCtx adds a log like Log, taking its correlation IDs from ctx
+func (st *storeImplementation) LogCtx(ctx context.Context, logEntry LogInterface) error {
 	if logEntry == nil {
 		return errors.New("log entry is nil")
 	}
 
-	return st.LogCreate(context.Background(), logEntry)
+	st.enrichLog(ctx, logEntry)
+
+	if st.deduplicator != nil {
+		st.deduplicator.add(st, logEntry)
+		return nil
+	}
+
+	return st.insertLog(ctx, logEntry)
 }
 
 // Debug adds a debug log
@@ -290,18 +591,39 @@ func (st *storeImplementation) ErrorWithContext(message string, context interfac
 	return st.Log(logEntry)
 }
 
-// Fatal adds an fatal log
+// ErrorErr adds an error log for err, recording each wrapped cause
+// (following both %w and errors.Join chains) and its type name
+func (st *storeImplementation) ErrorErr(err error, attrs ...any) error {
+	if err == nil {
+		return errors.New("error is nil")
+	}
+
+	fields, fieldsErr := parseFields(attrs)
+	if fieldsErr != nil {
+		return fieldsErr
+	}
+
+	fields = append(fields,
+		String("error", err.Error()),
+		String("error_type", fmt.Sprintf("%T", err)))
+
+	if causes := errorCauses(err); len(causes) > 0 {
+		fields = append(fields, Any("causes", causes))
+	}
+
+	return st.Log(st.newFieldsEntry(LEVEL_ERROR, err.Error(), fields))
+}
+
+// Fatal adds a fatal log, then acts on the configured FatalBehavior
 func (st *storeImplementation) Fatal(message string) error {
 	logEntry := NewLog().
 		SetLevel(LEVEL_FATAL).
 		SetMessage(message)
 
-	err := st.Log(logEntry)
-	// os.Exit(1)
-	return err
+	return st.fatal(logEntry)
 }
 
-// FatalWithContext adds a fatal log with context data
+// FatalWithContext adds a fatal log with context data, then acts on the configured FatalBehavior
 func (st *storeImplementation) FatalWithContext(message string, context interface{}) error {
 	contextBytes, err := json.Marshal(context)
 	if err != nil {
@@ -314,9 +636,7 @@ func (st *storeImplementation) FatalWithContext(message string, context interfac
 		SetMessage(message).
 		SetContext(string(contextBytes))
 
-	err = st.Log(logEntry)
-	// os.Exit(1)
-	return err
+	return st.fatal(logEntry)
 }
 
 // Info adds an info log
@@ -349,6 +669,7 @@ func (st *storeImplementation) Panic(message string) {
 		SetMessage(message)
 
 	st.Log(logEntry)
+	st.flushBeforeExit()
 	panic(message)
 }
 
@@ -361,11 +682,12 @@ func (st *storeImplementation) PanicWithContext(message string, context interfac
 	}
 
 	logEntry := NewLog().
-		SetLevel(LEVEL_FATAL).
+		SetLevel(LEVEL_PANIC).
 		SetMessage(message).
 		SetContext(string(contextBytes))
 
 	st.Log(logEntry)
+	st.flushBeforeExit()
 	panic(message)
 }
 
@@ -419,6 +741,69 @@ func (st *storeImplementation) WarnWithContext(message string, context interface
 	return st.Log(logEntry)
 }
 
+// == KEY/VALUE LOGGERS =======================================================
+
+// Tracew adds a trace log with key/value pairs or Fields as context data
+func (st *storeImplementation) Tracew(message string, keysAndValues ...any) error {
+	return st.logw(LEVEL_TRACE, message, keysAndValues)
+}
+
+// Debugw adds a debug log with key/value pairs or Fields as context data
+func (st *storeImplementation) Debugw(message string, keysAndValues ...any) error {
+	return st.logw(LEVEL_DEBUG, message, keysAndValues)
+}
+
+// Infow adds an info log with key/value pairs or Fields as context data
+func (st *storeImplementation) Infow(message string, keysAndValues ...any) error {
+	return st.logw(LEVEL_INFO, message, keysAndValues)
+}
+
+// Warnw adds a warn log with key/value pairs or Fields as context data
+func (st *storeImplementation) Warnw(message string, keysAndValues ...any) error {
+	return st.logw(LEVEL_WARNING, message, keysAndValues)
+}
+
+// Errorw adds an error log with key/value pairs or Fields as context data
+func (st *storeImplementation) Errorw(message string, keysAndValues ...any) error {
+	return st.logw(LEVEL_ERROR, message, keysAndValues)
+}
+
+// Fatalw adds a fatal log with key/value pairs or Fields as context data,
+// then acts on the configured FatalBehavior
+func (st *storeImplementation) Fatalw(message string, keysAndValues ...any) error {
+	fields, err := parseFields(keysAndValues)
+	if err != nil {
+		return err
+	}
+
+	return st.fatal(st.newFieldsEntry(LEVEL_FATAL, message, fields))
+}
+
+// logw adds a log with key/value pairs or Fields as context data. Nothing
+// is logged if the arguments are malformed.
+func (st *storeImplementation) logw(level string, message string, keysAndValues []any) error {
+	fields, err := parseFields(keysAndValues)
+	if err != nil {
+		return err
+	}
+
+	return st.Log(st.newFieldsEntry(level, message, fields))
+}
+
+// newFieldsEntry creates a log entry with the fields encoded as its context
+func (st *storeImplementation) newFieldsEntry(level string, message string, fields []Field) LogInterface {
+	contextJSON, err := encodeFields(fields)
+	if err != nil {
+		st.logger.Error("JSON encode error", "error", err)
+		contextJSON = "JSON encode error"
+	}
+
+	return NewLog().
+		SetLevel(level).
+		SetMessage(message).
+		SetContext(contextJSON)
+}
+
 // == CRUD ====================================================================
 
 // LogCreate adds a log
@@ -427,27 +812,163 @@ func (st *storeImplementation) LogCreate(ctx context.Context, logEntry LogInterf
 		return errors.New("log entry is nil")
 	}
 
-	id := logEntry.GetID()
-	if id == "" {
-		id = neatuid.GenerateShortID()
-		logEntry.SetID(id)
+	st.enrichLog(ctx, logEntry)
+
+	return st.insertLog(ctx, logEntry)
+}
+
+// LogCreateMany adds logs in bulk, with one insert per batch of rows
+func (st *storeImplementation) LogCreateMany(ctx context.Context, logEntries []LogInterface) error {
+	for _, logEntry := range logEntries {
+		if logEntry == nil {
+			return errors.New("log entry is nil")
+		}
+
+		st.enrichLog(ctx, logEntry)
+	}
+
+	return st.insertLogs(ctx, logEntries, defaultBatchSize)
+}
+
+// enrichLog adds the stack, bound fields, correlation IDs, resource and
+// tenant of the store to the entry. Entries are enriched once, before being
+// held back by the deduplicator or written.
+func (st *storeImplementation) enrichLog(ctx context.Context, logEntry LogInterface) {
+	st.captureStack(ctx, logEntry)
+	st.applyBoundFields(logEntry)
+	st.applyCorrelationIDs(ctx, logEntry)
+	st.applyResource(logEntry)
+	st.applyTenant(logEntry)
+}
+
+// insertLog writes the entry as it is, filling in the ID, time,
+// occurrences and fingerprint when missing
+func (st *storeImplementation) insertLog(ctx context.Context, logEntry LogInterface) error {
+	row := logRow(logEntry)
+
+	table, err := st.tableFor(ctx, logEntry.GetTime())
+	if err != nil {
+		return err
+	}
+
+	if err := st.db.Query().Table(table).Create(row); err != nil {
+		return err
+	}
+
+	st.publish(logEntry)
+	return nil
+}
+
+// insertLogs writes the entries like insertLog, in one transaction, with
+// one insert per batch of rows sharing a table. Nothing is written when an
+// insert fails, and the entries are only published once committed.
+func (st *storeImplementation) insertLogs(ctx context.Context, logEntries []LogInterface, batchSize int) error {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	tables := []string{}
+	rowsByTable := map[string][]map[string]any{}
+
+	// partitions are created before the transaction starts
+	for _, logEntry := range logEntries {
+		row := logRow(logEntry)
+
+		table, err := st.tableFor(ctx, logEntry.GetTime())
+		if err != nil {
+			return err
+		}
+
+		if _, ok := rowsByTable[table]; !ok {
+			tables = append(tables, table)
+		}
+
+		rowsByTable[table] = append(rowsByTable[table], row)
+	}
+
+	err := st.db.Transaction(func(tx contractsorm.Query) error {
+		for _, table := range tables {
+			rows := rowsByTable[table]
+
+			for start := 0; start < len(rows); start += batchSize {
+				end := min(start+batchSize, len(rows))
+				if err := tx.Table(table).Create(rows[start:end]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, logEntry := range logEntries {
+		st.publish(logEntry)
+	}
+
+	return nil
+}
+
+// tableFor returns the table holding the entries logged at t, creating
+// the partition when partitioning is enabled
+func (st *storeImplementation) tableFor(ctx context.Context, t time.Time) (string, error) {
+	if st.partitioning == PartitioningNone {
+		return st.logTableName, nil
+	}
+
+	p := st.partitionFor(t)
+	if err := st.ensurePartition(ctx, p); err != nil {
+		return "", err
+	}
+
+	return p.table, nil
+}
+
+// logRow fills in the ID, time, occurrences and fingerprint of the entry
+// when missing, and returns its column values
+func logRow(logEntry LogInterface) map[string]any {
+	if logEntry.GetID() == "" {
+		logEntry.SetID(neatuid.GenerateShortID())
+	}
+
+	if logEntry.GetTime().IsZero() {
+		logEntry.SetTime(time.Now().UTC())
+	}
+
+	if logEntry.GetOccurrences() < 1 {
+		logEntry.SetOccurrences(1)
 	}
 
-	t := logEntry.GetTime()
-	if t.IsZero() {
-		t = time.Now().UTC()
-		logEntry.SetTime(t)
+	if logEntry.GetLastSeen().IsZero() {
+		logEntry.SetLastSeen(logEntry.GetTime())
 	}
 
-	row := map[string]any{
-		COLUMN_ID:      logEntry.GetID(),
-		COLUMN_LEVEL:   logEntry.GetLevel(),
-		COLUMN_MESSAGE: logEntry.GetMessage(),
-		COLUMN_CONTEXT: logEntry.GetContext(),
-		COLUMN_TIME:    logEntry.GetTime(),
+	if logEntry.GetFingerprint() == "" && isIssueLevel(logEntry.GetLevel()) {
+		logEntry.SetFingerprint(Fingerprint(logEntry.GetMessage(), logEntry.GetStack()))
 	}
 
-	return st.db.Query().Table(st.logTableName).Create(row)
+	return map[string]any{
+		COLUMN_ID:              logEntry.GetID(),
+		COLUMN_LEVEL:           logEntry.GetLevel(),
+		COLUMN_MESSAGE:         logEntry.GetMessage(),
+		COLUMN_CONTEXT:         logEntry.GetContext(),
+		COLUMN_TIME:            logEntry.GetTime(),
+		COLUMN_OCCURRENCES:     logEntry.GetOccurrences(),
+		COLUMN_LAST_SEEN:       logEntry.GetLastSeen(),
+		COLUMN_FINGERPRINT:     logEntry.GetFingerprint(),
+		COLUMN_STACK:           logEntry.GetStack(),
+		COLUMN_TRACE_ID:        logEntry.GetTraceID(),
+		COLUMN_SPAN_ID:         logEntry.GetSpanID(),
+		COLUMN_REQUEST_ID:      logEntry.GetRequestID(),
+		COLUMN_SERVICE_NAME:    logEntry.GetServiceName(),
+		COLUMN_SERVICE_VERSION: logEntry.GetServiceVersion(),
+		COLUMN_HOST_NAME:       logEntry.GetHostName(),
+		COLUMN_ENVIRONMENT:     logEntry.GetEnvironment(),
+		COLUMN_INSTANCE_ID:     logEntry.GetInstanceID(),
+		COLUMN_TENANT_ID:       logEntry.GetTenantID(),
+	}
 }
 
 // LogDelete deletes a log
@@ -465,8 +986,13 @@ func (st *storeImplementation) LogDeleteByID(ctx context.Context, id string) err
 		return errors.New("log id is empty")
 	}
 
-	_, err := st.db.Query().
-		Table(st.logTableName).
+	if st.partitioning != PartitioningNone {
+		return st.forEachPartition(nil, func(view *storeImplementation) error {
+			return view.LogDeleteByID(ctx, id)
+		})
+	}
+
+	_, err := st.tableQuery().
 		Where(COLUMN_ID+" = ?", id).
 		Delete()
 
@@ -484,8 +1010,13 @@ func (st *storeImplementation) LogDeleteByIDs(ctx context.Context, ids []string)
 		args[i] = id
 	}
 
-	_, err := st.db.Query().
-		Table(st.logTableName).
+	if st.partitioning != PartitioningNone {
+		return st.forEachPartition(nil, func(view *storeImplementation) error {
+			return view.LogDeleteByIDs(ctx, ids)
+		})
+	}
+
+	_, err := st.tableQuery().
 		WhereIn(COLUMN_ID, args).
 		Delete()
 
@@ -523,48 +1054,100 @@ func (st *storeImplementation) LogList(ctx context.Context, query LogQueryInterf
 		return []LogInterface{}, err
 	}
 
-	q := st.buildQuery(query)
-
 	var results []map[string]any
-	err := q.Get(&results)
-	if err != nil {
+
+	if st.partitioning != PartitioningNone {
+		partitioned, err := st.partitionedLogList(query)
+		if err != nil {
+			return []LogInterface{}, err
+		}
+		results = partitioned
+	} else if err := st.buildQuery(query).Get(&results); err != nil {
 		return []LogInterface{}, err
 	}
 
-	list := []LogInterface{}
+	list := make([]LogInterface, 0, len(results))
 	for _, result := range results {
-		id := ""
-		if v, ok := result[COLUMN_ID].(string); ok {
-			id = v
-		}
-		level := ""
-		if v, ok := result[COLUMN_LEVEL].(string); ok {
-			level = v
-		}
-		message := ""
-		if v, ok := result[COLUMN_MESSAGE].(string); ok {
-			message = v
-		}
-		contextStr := ""
-		if v, ok := result[COLUMN_CONTEXT].(string); ok {
-			contextStr = v
-		}
+		list = append(list, logFromResult(result))
+	}
 
-		var t time.Time
-		if v, ok := result[COLUMN_TIME]; ok {
-			switch vt := v.(type) {
-			case time.Time:
-				t = vt
-			case string:
-				t = carbon.Parse(vt, carbon.UTC).StdTime()
-			}
-		}
+	return list, nil
+}
 
-		logEntry := NewLogWithData(id, level, message, contextStr, t)
-		list = append(list, logEntry)
+// logFromResult converts a scanned row to a log entry
+func logFromResult(result map[string]any) LogInterface {
+	id := ""
+	if v, ok := result[COLUMN_ID].(string); ok {
+		id = v
+	}
+	level := ""
+	if v, ok := result[COLUMN_LEVEL].(string); ok {
+		level = v
+	}
+	message := ""
+	if v, ok := result[COLUMN_MESSAGE].(string); ok {
+		message = v
+	}
+	contextStr := ""
+	if v, ok := result[COLUMN_CONTEXT].(string); ok {
+		contextStr = v
 	}
 
-	return list, nil
+	t := toTime(result[COLUMN_TIME])
+
+	logEntry := NewLogWithData(id, level, message, contextStr, t)
+
+	if occurrences := toInt64(result[COLUMN_OCCURRENCES]); occurrences > 0 {
+		logEntry.SetOccurrences(int(occurrences))
+	}
+
+	logEntry.SetLastSeen(toTime(result[COLUMN_LAST_SEEN]))
+
+	if v, ok := result[COLUMN_FINGERPRINT].(string); ok {
+		logEntry.SetFingerprint(v)
+	}
+
+	if v, ok := result[COLUMN_STACK].(string); ok {
+		logEntry.SetStack(v)
+	}
+
+	if v, ok := result[COLUMN_TRACE_ID].(string); ok {
+		logEntry.SetTraceID(v)
+	}
+
+	if v, ok := result[COLUMN_SPAN_ID].(string); ok {
+		logEntry.SetSpanID(v)
+	}
+
+	if v, ok := result[COLUMN_REQUEST_ID].(string); ok {
+		logEntry.SetRequestID(v)
+	}
+
+	if v, ok := result[COLUMN_SERVICE_NAME].(string); ok {
+		logEntry.SetServiceName(v)
+	}
+
+	if v, ok := result[COLUMN_SERVICE_VERSION].(string); ok {
+		logEntry.SetServiceVersion(v)
+	}
+
+	if v, ok := result[COLUMN_HOST_NAME].(string); ok {
+		logEntry.SetHostName(v)
+	}
+
+	if v, ok := result[COLUMN_ENVIRONMENT].(string); ok {
+		logEntry.SetEnvironment(v)
+	}
+
+	if v, ok := result[COLUMN_INSTANCE_ID].(string); ok {
+		logEntry.SetInstanceID(v)
+	}
+
+	if v, ok := result[COLUMN_TENANT_ID].(string); ok {
+		logEntry.SetTenantID(v)
+	}
+
+	return logEntry
 }
 
 // LogCount returns the total number of logs that match the given query
@@ -577,6 +1160,10 @@ func (st *storeImplementation) LogCount(ctx context.Context, query LogQueryInter
 		return 0, err
 	}
 
+	if st.partitioning != PartitioningNone {
+		return st.partitionedLogCount(query)
+	}
+
 	q := st.buildQuery(query)
 
 	var count int64
@@ -590,9 +1177,54 @@ func (st *storeImplementation) LogCount(ctx context.Context, query LogQueryInter
 
 // == QUERY BUILDER ==========================================================
 
+// tableQuery returns a query on the log table, scoped to the tenant of a
+// ForTenant view
+func (st *storeImplementation) tableQuery() contractsorm.Query {
+	q := st.db.Query().Table(st.logTableName)
+
+	if st.tenantScoped {
+		q = q.Where(COLUMN_TENANT_ID+" = ?", st.tenantID)
+	}
+
+	return q
+}
+
 // buildQuery builds a neat query from the log query interface.
 func (st *storeImplementation) buildQuery(query LogQueryInterface) contractsorm.Query {
-	q := st.db.Query().Table(st.logTableName)
+	q := st.buildFilterQuery(query)
+
+	if query == nil {
+		return q
+	}
+
+	if query.IsLimitSet() && query.GetLimit() > 0 {
+		q = q.Limit(query.GetLimit())
+	}
+
+	if query.IsOffsetSet() && query.GetOffset() > 0 {
+		q = q.Offset(query.GetOffset())
+	}
+
+	return st.orderQuery(q, query)
+}
+
+// orderQuery applies the ordering of the log query interface
+func (st *storeImplementation) orderQuery(q contractsorm.Query, query LogQueryInterface) contractsorm.Query {
+	if query.IsOrderBySet() && query.GetOrderBy() != "" {
+		direction := "desc"
+		if query.IsOrderDirectionSet() && query.GetOrderDirection() != "" {
+			direction = query.GetOrderDirection()
+		}
+		q = q.OrderBy(query.GetOrderBy(), direction)
+	}
+
+	return q
+}
+
+// buildFilterQuery builds a neat query applying only the filters of the
+// log query interface, without limit, offset or ordering.
+func (st *storeImplementation) buildFilterQuery(query LogQueryInterface) contractsorm.Query {
+	q := st.tableQuery()
 
 	if query == nil {
 		return q
@@ -646,21 +1278,140 @@ func (st *storeImplementation) buildQuery(query LogQueryInterface) contractsorm.
 		q = q.Where(COLUMN_TIME+" <= ?", query.GetTimeLte())
 	}
 
-	if query.IsLimitSet() && query.GetLimit() > 0 {
-		q = q.Limit(query.GetLimit())
+	if query.IsFingerprintSet() && query.GetFingerprint() != "" {
+		q = q.Where(COLUMN_FINGERPRINT+" = ?", query.GetFingerprint())
 	}
 
-	if query.IsOffsetSet() && query.GetOffset() > 0 {
-		q = q.Offset(query.GetOffset())
+	if query.IsTraceIDSet() && query.GetTraceID() != "" {
+		q = q.Where(COLUMN_TRACE_ID+" = ?", query.GetTraceID())
 	}
 
-	if query.IsOrderBySet() && query.GetOrderBy() != "" {
-		direction := "desc"
-		if query.IsOrderDirectionSet() && query.GetOrderDirection() != "" {
-			direction = query.GetOrderDirection()
-		}
-		q = q.OrderBy(query.GetOrderBy(), direction)
+	if query.IsSpanIDSet() && query.GetSpanID() != "" {
+		q = q.Where(COLUMN_SPAN_ID+" = ?", query.GetSpanID())
+	}
+
+	if query.IsRequestIDSet() && query.GetRequestID() != "" {
+		q = q.Where(COLUMN_REQUEST_ID+" = ?", query.GetRequestID())
+	}
+
+	if query.IsServiceNameSet() && query.GetServiceName() != "" {
+		q = q.Where(COLUMN_SERVICE_NAME+" = ?", query.GetServiceName())
+	}
+
+	if query.IsServiceVersionSet() && query.GetServiceVersion() != "" {
+		q = q.Where(COLUMN_SERVICE_VERSION+" = ?", query.GetServiceVersion())
+	}
+
+	if query.IsHostNameSet() && query.GetHostName() != "" {
+		q = q.Where(COLUMN_HOST_NAME+" = ?", query.GetHostName())
+	}
+
+	if query.IsEnvironmentSet() && query.GetEnvironment() != "" {
+		q = q.Where(COLUMN_ENVIRONMENT+" = ?", query.GetEnvironment())
+	}
+
+	if query.IsInstanceIDSet() && query.GetInstanceID() != "" {
+		q = q.Where(COLUMN_INSTANCE_ID+" = ?", query.GetInstanceID())
+	}
+
+	if query.IsTenantIDSet() && query.GetTenantID() != "" {
+		q = q.Where(COLUMN_TENANT_ID+" = ?", query.GetTenantID())
 	}
 
 	return q
 }
+
+// == HELPERS =================================================================
+
+// compareValues orders two scanned column values, nil first
+func compareValues(a any, b any) int {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		default:
+			return 1
+		}
+	}
+
+	_, aIsTime := a.(time.Time)
+	_, bIsTime := b.(time.Time)
+	if aIsTime || bIsTime {
+		return toTime(a).Compare(toTime(b))
+	}
+
+	aNumber, aIsNumber := toFloat64(a)
+	bNumber, bIsNumber := toFloat64(b)
+	if aIsNumber && bIsNumber {
+		return cmp.Compare(aNumber, bNumber)
+	}
+
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// toFloat64 converts a scanned numeric column value to float64
+func toFloat64(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+
+	return 0, false
+}
+
+// toDateTimeString formats a time the way it is stored, for comparisons
+// in where clauses
+func toDateTimeString(t time.Time) string {
+	return carbon.CreateFromStdTime(t).SetTimezone(carbon.UTC).ToDateTimeString()
+}
+
+// toTime converts a scanned column value to time.Time
+func toTime(value any) time.Time {
+	switch v := value.(type) {
+	case time.Time:
+		return v
+	case string:
+		if v == "" {
+			return time.Time{}
+		}
+		return carbon.Parse(v, carbon.UTC).StdTime()
+	case []byte:
+		return toTime(string(v))
+	}
+
+	return time.Time{}
+}
+
+// toInt64 converts a scanned numeric column value to int64
+func toInt64(value any) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint64:
+		return int64(v)
+	case float64:
+		return int64(v)
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	case []byte:
+		n, _ := strconv.ParseInt(string(v), 10, 64)
+		return n
+	}
+
+	return 0
+}
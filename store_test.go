@@ -85,6 +85,38 @@ func Test_Store_AutoMigrate(t *testing.T) {
 	}
 }
 
+func Test_Store_MigrateUp_AddsMissingColumns(t *testing.T) {
+	db := InitDB()
+
+	_, err := db.Exec(`CREATE TABLE "log_legacy" ("id" varchar(40) not null primary key, "level" varchar(20) not null, "message" text not null, "context" text not null, "time" datetime not null)`)
+	if err != nil {
+		t.Fatalf("could not create legacy table: %v", err)
+	}
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_legacy",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	if err := s.Info("after upgrade"); err != nil {
+		t.Fatalf("unexpected error writing to upgraded table: %v", err)
+	}
+
+	logs, err := s.LogList(context.Background(), LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	if len(logs) != 1 || logs[0].GetOccurrences() != 1 {
+		t.Fatalf("expected 1 entry with 1 occurrence, got %d entries", len(logs))
+	}
+}
+
 func Test_Store_Log(t *testing.T) {
 	db := InitDB()
 
@@ -189,7 +221,8 @@ func Test_Store_ErrorWithContext(t *testing.T) {
 	}
 }
 
-// Fatal methods uses system level API to terminate program (os.Exit)
+// Fatal methods use system level API to terminate program (os.Exit),
+// unless FatalBehaviorReturn is set
 func Test_Store_Fatal(t *testing.T) {
 	db := InitDB()
 
@@ -197,6 +230,7 @@ func Test_Store_Fatal(t *testing.T) {
 		DB:                 db,
 		LogTableName:       "log",
 		AutomigrateEnabled: true,
+		FatalBehavior:      FatalBehaviorReturn,
 	})
 
 	if err != nil {
@@ -216,6 +250,7 @@ func Test_Store_FatalWithContext(t *testing.T) {
 		DB:                 db,
 		LogTableName:       "log",
 		AutomigrateEnabled: true,
+		FatalBehavior:      FatalBehaviorReturn,
 	})
 
 	if err != nil {
@@ -0,0 +1,354 @@
+package logstore
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultSubscribeBufferSize is the default capacity of a subscription channel
+const defaultSubscribeBufferSize = 100
+
+// defaultSubscribeLookback is how far back polling reads again by default
+const defaultSubscribeLookback = 5 * time.Second
+
+// SubscribeOptions define the options of Subscribe
+type SubscribeOptions struct {
+	// PollInterval, when greater than zero, reads new entries from the
+	// database at the interval, which also delivers the entries written by
+	// other processes. By default only the entries written through this
+	// store are delivered, as they are created.
+	PollInterval time.Duration
+
+	// Lookback is how far before the newest entry seen polling reads
+	// again, to catch entries committed late with an earlier time, by
+	// default 5 seconds. It should exceed the deduplication window and
+	// the clock skew between the writing processes.
+	Lookback time.Duration
+
+	// BufferSize is the capacity of the channel, by default 100. Without
+	// polling, entries are dropped rather than blocking the writer while
+	// the channel is full.
+	BufferSize int
+}
+
+// Subscribe delivers the entries created after the call which match the
+// query filters, until the context is done, when the channel is closed.
+// The limit, offset and order of the query are ignored, and the query
+// must not be changed once subscribed.
+func (st *storeImplementation) Subscribe(ctx context.Context, query LogQueryInterface, opts ...SubscribeOptions) (<-chan LogInterface, error) {
+	if query == nil {
+		query = LogQuery()
+	}
+
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	options := SubscribeOptions{}
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	if options.BufferSize <= 0 {
+		options.BufferSize = defaultSubscribeBufferSize
+	}
+
+	if options.Lookback <= 0 {
+		options.Lookback = defaultSubscribeLookback
+	}
+
+	ch := make(chan LogInterface, options.BufferSize)
+
+	if options.PollInterval > 0 {
+		poller := &subscriptionPoller{
+			store:   st,
+			query:   query,
+			options: options,
+			seen:    map[string]time.Time{},
+		}
+
+		// entries already in the lookback window are not new, and are
+		// only marked as seen
+		if err := poller.poll(ctx, nil); err != nil {
+			return nil, err
+		}
+
+		go poller.run(ctx, ch)
+
+		return ch, nil
+	}
+
+	s := &subscription{
+		table:        st.logTableName,
+		tenantID:     st.tenantID,
+		tenantScoped: st.tenantScoped,
+		query:        query,
+		ch:           ch,
+	}
+
+	st.subscriptions.add(s)
+
+	go func() {
+		<-ctx.Done()
+		st.subscriptions.remove(s)
+	}()
+
+	return ch, nil
+}
+
+// publish delivers the created entry to the in-process subscriptions
+func (st *storeImplementation) publish(logEntry LogInterface) {
+	st.subscriptions.publish(st.logTableName, logEntry, st.logger)
+}
+
+// == IN-PROCESS ==============================================================
+
+// subscription is an in-process subscription to the entries of a table
+type subscription struct {
+	table        string
+	tenantID     string
+	tenantScoped bool
+	query        LogQueryInterface
+	ch           chan LogInterface
+	dropping     atomic.Bool
+}
+
+// subscriptionRegistry holds the in-process subscriptions, shared by the
+// store and its views
+type subscriptionRegistry struct {
+	mutex         sync.RWMutex
+	subscriptions map[*subscription]struct{}
+}
+
+func newSubscriptionRegistry() *subscriptionRegistry {
+	return &subscriptionRegistry{subscriptions: map[*subscription]struct{}{}}
+}
+
+func (r *subscriptionRegistry) add(s *subscription) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.subscriptions[s] = struct{}{}
+}
+
+// remove unregisters the subscription and closes its channel
+func (r *subscriptionRegistry) remove(s *subscription) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.subscriptions, s)
+	close(s.ch)
+}
+
+// publish sends a copy of the entry to the matching subscriptions, without
+// blocking. Entries are dropped for subscriptions whose channel is full.
+func (r *subscriptionRegistry) publish(table string, logEntry LogInterface, logger *slog.Logger) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if len(r.subscriptions) == 0 {
+		return
+	}
+
+	var entry LogInterface
+
+	for s := range r.subscriptions {
+		if s.table != table {
+			continue
+		}
+
+		if s.tenantScoped && s.tenantID != logEntry.GetTenantID() {
+			continue
+		}
+
+		if !matchesQuery(s.query, logEntry) {
+			continue
+		}
+
+		if entry == nil {
+			entry = NewLogRecord(logEntry).ToLog()
+		}
+
+		select {
+		case s.ch <- entry:
+			s.dropping.Store(false)
+		default:
+			if !s.dropping.Swap(true) {
+				logger.Warn("Subscribe: subscriber is too slow, dropping entries",
+					"table", table)
+			}
+		}
+	}
+}
+
+// == POLLING =================================================================
+
+// subscriptionPoller reads new entries from the database. Times are stored
+// to the second and IDs are not ordered, so each poll reads the entries from
+// the lookback window before the newest time seen, and skips the IDs already
+// delivered, which are remembered for as long as they are in the window.
+type subscriptionPoller struct {
+	store   *storeImplementation
+	query   LogQueryInterface
+	options SubscribeOptions
+	newest  time.Time
+	seen    map[string]time.Time
+}
+
+// run polls at the interval until the context is done, then closes the channel
+func (p *subscriptionPoller) run(ctx context.Context, ch chan LogInterface) {
+	defer close(ch)
+
+	ticker := time.NewTicker(p.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := p.poll(ctx, ch); err != nil && ctx.Err() == nil {
+			p.store.logger.Error("Subscribe: polling failed", "error", err)
+		}
+	}
+}
+
+// poll sends the entries not seen yet to the channel, or only marks them as
+// seen when the channel is nil
+func (p *subscriptionPoller) poll(ctx context.Context, ch chan LogInterface) error {
+	since := p.lookbackStart(time.Now().UTC())
+	if !p.newest.IsZero() {
+		since = p.lookbackStart(p.newest)
+	}
+
+	err := p.store.forEachLogBatchSince(ctx, p.query, since, 0, func(batch []LogInterface) error {
+		for _, logEntry := range batch {
+			if _, ok := p.seen[logEntry.GetID()]; ok {
+				continue
+			}
+
+			p.seen[logEntry.GetID()] = logEntry.GetTime()
+
+			if logEntry.GetTime().After(p.newest) {
+				p.newest = logEntry.GetTime()
+			}
+
+			if ch == nil {
+				continue
+			}
+
+			select {
+			case ch <- logEntry:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// only the IDs the next poll can read again are kept
+	cutoff := p.lookbackStart(p.newest)
+	for id, t := range p.seen {
+		if t.Before(cutoff) {
+			delete(p.seen, id)
+		}
+	}
+
+	return nil
+}
+
+// lookbackStart returns the time polling reads from, the lookback window
+// before t, truncated to whole seconds as times are compared in queries
+func (p *subscriptionPoller) lookbackStart(t time.Time) time.Time {
+	return t.Add(-p.options.Lookback).Truncate(time.Second)
+}
+
+// == MATCHING ================================================================
+
+// matchesQuery reports whether the entry passes the query filters, the way
+// buildFilterQuery selects rows. Contains filters ignore case, like LIKE.
+func matchesQuery(query LogQueryInterface, logEntry LogInterface) bool {
+	if query == nil {
+		return true
+	}
+
+	if query.IsIDSet() && query.GetID() != "" && logEntry.GetID() != query.GetID() {
+		return false
+	}
+
+	if query.IsIDInSet() && len(query.GetIDIn()) > 0 && !slices.Contains(query.GetIDIn(), logEntry.GetID()) {
+		return false
+	}
+
+	if query.IsLevelSet() && query.GetLevel() != "" && logEntry.GetLevel() != query.GetLevel() {
+		return false
+	}
+
+	if query.IsLevelInSet() && len(query.GetLevelIn()) > 0 && !slices.Contains(query.GetLevelIn(), logEntry.GetLevel()) {
+		return false
+	}
+
+	if query.IsMessageContainsSet() && query.GetMessageContains() != "" && !containsFold(logEntry.GetMessage(), query.GetMessageContains()) {
+		return false
+	}
+
+	if query.IsMessageNotContainsSet() && query.GetMessageNotContains() != "" && containsFold(logEntry.GetMessage(), query.GetMessageNotContains()) {
+		return false
+	}
+
+	if query.IsContextContainsSet() && query.GetContextContains() != "" && !containsFold(logEntry.GetContext(), query.GetContextContains()) {
+		return false
+	}
+
+	if query.IsContextNotContainsSet() && query.GetContextNotContains() != "" && containsFold(logEntry.GetContext(), query.GetContextNotContains()) {
+		return false
+	}
+
+	if query.IsTimeGteSet() && query.GetTimeGte() != "" && logEntry.GetTime().Truncate(time.Second).Before(toTime(query.GetTimeGte())) {
+		return false
+	}
+
+	if query.IsTimeLteSet() && query.GetTimeLte() != "" && logEntry.GetTime().Truncate(time.Second).After(toTime(query.GetTimeLte())) {
+		return false
+	}
+
+	fields := []struct {
+		isSet  bool
+		filter string
+		value  string
+	}{
+		{query.IsFingerprintSet(), query.GetFingerprint(), logEntry.GetFingerprint()},
+		{query.IsTraceIDSet(), query.GetTraceID(), logEntry.GetTraceID()},
+		{query.IsSpanIDSet(), query.GetSpanID(), logEntry.GetSpanID()},
+		{query.IsRequestIDSet(), query.GetRequestID(), logEntry.GetRequestID()},
+		{query.IsServiceNameSet(), query.GetServiceName(), logEntry.GetServiceName()},
+		{query.IsServiceVersionSet(), query.GetServiceVersion(), logEntry.GetServiceVersion()},
+		{query.IsHostNameSet(), query.GetHostName(), logEntry.GetHostName()},
+		{query.IsEnvironmentSet(), query.GetEnvironment(), logEntry.GetEnvironment()},
+		{query.IsInstanceIDSet(), query.GetInstanceID(), logEntry.GetInstanceID()},
+		{query.IsTenantIDSet(), query.GetTenantID(), logEntry.GetTenantID()},
+	}
+
+	for _, field := range fields {
+		if field.isSet && field.filter != "" && field.value != field.filter {
+			return false
+		}
+	}
+
+	return true
+}
+
+// containsFold reports whether s contains substr, ignoring case
+func containsFold(s string, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,227 @@
+package logstore
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_Store_Subscribe(t *testing.T) {
+	db := InitDB()
+	db.SetMaxOpenConns(1)
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_subscribe",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	errorLogs, err := s.Subscribe(ctx, LogQuery().SetLevel(LEVEL_ERROR).SetMessageContains("PAYMENT"))
+	if err != nil {
+		t.Fatalf("unexpected error from Subscribe: %v", err)
+	}
+
+	tenantLogs, err := s.ForTenant("acme").Subscribe(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error from Subscribe: %v", err)
+	}
+
+	if err := s.Info("payment started"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Error("payment failed"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.ForTenant("acme").Error("payment failed for acme"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.LogCreateMany(ctx, []LogInterface{
+		NewLog().SetLevel(LEVEL_ERROR).SetMessage("payment declined"),
+		NewLog().SetLevel(LEVEL_ERROR).SetMessage("login failed"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := []string{}
+	for range 3 {
+		select {
+		case logEntry := <-errorLogs:
+			messages = append(messages, logEntry.GetMessage())
+		case <-time.After(time.Second):
+			t.Fatalf("expected 3 entries, got %v", messages)
+		}
+	}
+
+	expected := []string{"payment failed", "payment failed for acme", "payment declined"}
+	for i, message := range expected {
+		if messages[i] != message {
+			t.Fatalf("expected %v, got %v", expected, messages)
+		}
+	}
+
+	select {
+	case logEntry := <-tenantLogs:
+		if logEntry.GetMessage() != "payment failed for acme" || logEntry.GetTenantID() != "acme" {
+			t.Fatalf("unexpected tenant entry: %s", logEntry.GetMessage())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the tenant entry")
+	}
+
+	cancel()
+
+	for range errorLogs {
+		t.Fatal("expected no more entries")
+	}
+
+	if _, ok := <-tenantLogs; ok {
+		t.Fatal("expected the channel to be closed")
+	}
+}
+
+func Test_Store_Subscribe_Polling(t *testing.T) {
+	db := InitDB()
+	db.SetMaxOpenConns(1)
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_subscribe_polling",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	// another process, writing to the same table
+	writer, err := NewStore(NewStoreOptions{
+		DB:           db,
+		LogTableName: "log_subscribe_polling",
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	at := time.Now().UTC().Truncate(time.Second)
+
+	if err := writer.LogCreate(context.Background(), NewLog().SetLevel(LEVEL_INFO).SetMessage("before").SetTime(at)); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logs, err := s.Subscribe(ctx, LogQuery().SetLevel(LEVEL_INFO), SubscribeOptions{
+		PollInterval: 10 * time.Millisecond,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error from Subscribe: %v", err)
+	}
+
+	received := map[string]int{}
+	receive := func(count int) {
+		t.Helper()
+		for range count {
+			select {
+			case logEntry := <-logs:
+				received[logEntry.GetMessage()]++
+			case <-time.After(2 * time.Second):
+				t.Fatalf("expected %d entries, got %v", count, received)
+			}
+		}
+	}
+
+	// entries sharing the time of an entry already delivered, with random
+	// IDs, written across polls
+	for _, message := range []string{"first", "second", "third"} {
+		if err := writer.LogCreate(ctx, NewLog().SetLevel(LEVEL_INFO).SetMessage(message).SetTime(at)); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := writer.LogCreate(ctx, NewLog().SetLevel(LEVEL_DEBUG).SetMessage("filtered").SetTime(at)); err != nil {
+			t.Fatal(err)
+		}
+
+		receive(1)
+	}
+
+	if err := writer.LogCreate(ctx, NewLog().SetLevel(LEVEL_INFO).SetMessage("late").SetTime(at.Add(-time.Second))); err != nil {
+		t.Fatal(err)
+	}
+
+	receive(1)
+
+	select {
+	case logEntry := <-logs:
+		t.Fatalf("unexpected entry: %s", logEntry.GetMessage())
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	expected := map[string]int{"first": 1, "second": 1, "third": 1, "late": 1}
+	for message, count := range expected {
+		if received[message] != count {
+			t.Fatalf("expected %v, got %v", expected, received)
+		}
+	}
+
+	if len(received) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, received)
+	}
+}
+
+func Test_SubscriptionPoller_FractionalLookback(t *testing.T) {
+	s, err := NewStore(NewStoreOptions{
+		DB:                 InitDB(),
+		LogTableName:       "log_subscribe_lookback",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+	at := time.Date(2026, time.October, 19, 14, 0, 10, 0, time.UTC)
+
+	for _, logEntry := range []LogInterface{
+		NewLog().SetLevel(LEVEL_INFO).SetMessage("older").SetTime(at.Add(-2 * time.Second)),
+		NewLog().SetLevel(LEVEL_INFO).SetMessage("newest").SetTime(at),
+	} {
+		if err := s.LogCreate(ctx, logEntry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	poller := &subscriptionPoller{
+		store:   s.(*storeImplementation),
+		query:   LogQuery(),
+		options: SubscribeOptions{Lookback: 1500 * time.Millisecond},
+		newest:  at,
+		seen:    map[string]time.Time{},
+	}
+
+	if err := poller.poll(ctx, nil); err != nil {
+		t.Fatalf("unexpected error from poll: %v", err)
+	}
+
+	// the query reads from whole seconds, so the older entry is read again
+	ch := make(chan LogInterface, 10)
+	if err := poller.poll(ctx, ch); err != nil {
+		t.Fatalf("unexpected error from poll: %v", err)
+	}
+
+	if len(ch) != 0 {
+		t.Fatalf("expected no entry to be delivered again, got %s", (<-ch).GetMessage())
+	}
+}
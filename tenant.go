@@ -0,0 +1,103 @@
+package logstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// ForTenant returns a view of the store for one tenant. Every entry written
+// through the view is stamped with the tenant ID, and every list, count and
+// delete is restricted to the tenant's entries, whatever the query says.
+//
+// With the TenantTables option the view uses a table of its own, named by
+// TenantTableName, which is migrated the first time the tenant is used.
+//
+// An empty tenant ID scopes the view to the entries without a tenant, never
+// to every tenant. A tenant view cannot be widened: calling ForTenant on it
+// with a different tenant ID returns the view unchanged.
+func (st *storeImplementation) ForTenant(tenantID string) StoreInterface {
+	if st.tenantScoped {
+		if tenantID != st.tenantID {
+			st.logger.Warn("ForTenant: view is already scoped to a tenant",
+				"tenant_id", st.tenantID,
+				"requested_tenant_id", tenantID)
+		}
+		return st
+	}
+
+	child := *st
+	child.tenantID = tenantID
+	child.tenantScoped = true
+
+	if st.tenantTables {
+		child.logTableName = st.tenantTableName(st.logTableName, tenantID)
+		child.migrateTenantTable()
+	}
+
+	return &child
+}
+
+// isSharedTenantView reports whether the store is a tenant view using the
+// log table shared by all tenants
+func (st *storeImplementation) isSharedTenantView() bool {
+	return st.tenantScoped && !st.tenantTables
+}
+
+// applyTenant stamps the entry with the tenant of a ForTenant view,
+// replacing any tenant ID set by the caller
+func (st *storeImplementation) applyTenant(logEntry LogInterface) {
+	if !st.tenantScoped {
+		return
+	}
+
+	logEntry.SetTenantID(st.tenantID)
+}
+
+// migrateTenantTable creates the table of a tenant once per store
+func (st *storeImplementation) migrateTenantTable() {
+	if _, loaded := st.migratedTables.LoadOrStore(st.logTableName, true); loaded {
+		return
+	}
+
+	if err := st.MigrateUp(context.Background()); err != nil {
+		st.migratedTables.Delete(st.logTableName)
+		st.logger.Error("ForTenant: migrating tenant table failed",
+			"table", st.logTableName,
+			"error", err)
+	}
+}
+
+// tenantTableNameMaxID is the length the tenant ID is cut to in table names
+const tenantTableNameMaxID = 32
+
+// DefaultTenantTableName names the table of a tenant by appending the
+// tenant ID and a hash of it to the log table name, i.e.
+// "log_acme_corp_83f386d6300f". Characters other than letters, digits and
+// underscores are replaced with underscores, and the ID is cut to 32
+// characters; the hash keeps IDs which read the same, such as "Acme" and
+// "acme" or "acme-corp" and "acme.corp", in tables of their own.
+func DefaultTenantTableName(logTableName string, tenantID string) string {
+	var name strings.Builder
+	name.WriteString(logTableName)
+	name.WriteString("_")
+
+	for i, r := range []rune(strings.ToLower(tenantID)) {
+		if i == tenantTableNameMaxID {
+			break
+		}
+
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			name.WriteRune(r)
+		} else {
+			name.WriteRune('_')
+		}
+	}
+
+	hash := sha256.Sum256([]byte(tenantID))
+	name.WriteString("_")
+	name.WriteString(hex.EncodeToString(hash[:6]))
+
+	return name.String()
+}
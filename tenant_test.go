@@ -0,0 +1,271 @@
+package logstore
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func Test_Store_ForTenant(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_tenant",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+	acme := s.ForTenant("acme")
+	globex := s.ForTenant("globex")
+
+	if err := acme.Info("acme 1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := acme.Log(NewLog().SetLevel(LEVEL_INFO).SetMessage("acme 2").SetTenantID("globex")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := globex.Info("globex 1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	count, err := acme.LogCount(ctx, LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogCount: %v", err)
+	}
+
+	if count != 2 {
+		t.Fatalf("expected 2 acme logs, got %d", count)
+	}
+
+	logs, err := acme.LogList(ctx, LogQuery().SetTenantID("globex"))
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	if len(logs) != 0 {
+		t.Fatalf("expected a tenant view not to list other tenants, got %d logs", len(logs))
+	}
+
+	globexLogs, err := globex.LogList(ctx, LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	if len(globexLogs) != 1 || globexLogs[0].GetTenantID() != "globex" {
+		t.Fatalf("expected 1 globex log, got %d", len(globexLogs))
+	}
+
+	if err := acme.LogDeleteByID(ctx, globexLogs[0].GetID()); err != nil {
+		t.Fatalf("unexpected error from LogDeleteByID: %v", err)
+	}
+
+	if found, _ := s.LogFindByID(ctx, globexLogs[0].GetID()); found == nil {
+		t.Fatal("expected a tenant view not to delete other tenants' logs")
+	}
+
+	if found, _ := acme.LogFindByID(ctx, globexLogs[0].GetID()); found != nil {
+		t.Fatal("expected a tenant view not to find other tenants' logs")
+	}
+
+	if acme.ForTenant("globex") != acme {
+		t.Fatal("expected a tenant view not to be widened to another tenant")
+	}
+
+	total, err := s.LogCount(ctx, LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogCount: %v", err)
+	}
+
+	if total != 3 {
+		t.Fatalf("expected the parent store to see all 3 logs, got %d", total)
+	}
+}
+
+func Test_Store_ForTenant_TenantTables(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_tenant_tables",
+		AutomigrateEnabled: true,
+		TenantTables:       true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+	acme := s.ForTenant("Acme-Corp")
+
+	if acme.GetLogTableName() != DefaultTenantTableName("log_tenant_tables", "Acme-Corp") {
+		t.Fatalf("unexpected tenant table name %q", acme.GetLogTableName())
+	}
+
+	if err := acme.Info("acme 1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	count, err := acme.LogCount(ctx, LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogCount: %v", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("expected 1 log in the tenant table, got %d", count)
+	}
+
+	shared, err := s.LogCount(ctx, LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogCount: %v", err)
+	}
+
+	if shared != 0 {
+		t.Fatalf("expected the shared table to stay empty, got %d", shared)
+	}
+}
+
+func Test_Store_ForTenant_SharedTable(t *testing.T) {
+	s, err := NewStore(NewStoreOptions{
+		DB:                 InitDB(),
+		LogTableName:       "log_tenant_shared",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+
+	if err := s.ForTenant("b").Info("b 1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	acme := s.ForTenant("a")
+
+	if err := acme.MigrateDown(ctx); err == nil {
+		t.Fatal("expected an error dropping the shared table from a tenant view")
+	}
+
+	acme.SetLogTableName("log_other")
+
+	if acme.GetLogTableName() != "log_tenant_shared" {
+		t.Fatalf("expected the table name to be unchanged, got %q", acme.GetLogTableName())
+	}
+
+	count, err := s.ForTenant("b").LogCount(ctx, LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogCount: %v", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("expected the logs of tenant b to be kept, got %d", count)
+	}
+}
+
+func Test_Store_ForTenant_EmptyID(t *testing.T) {
+	s, err := NewStore(NewStoreOptions{
+		DB:                 InitDB(),
+		LogTableName:       "log_tenant_empty",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	ctx := context.Background()
+
+	if err := s.ForTenant("acme").Info("acme 1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := s.Log(NewLog().SetLevel(LEVEL_INFO).SetMessage("untenanted").SetTenantID("other")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := s.Info("no tenant"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	empty := s.ForTenant("")
+
+	// the view is scoped, so it cannot be widened either
+	if empty.ForTenant("acme") != empty {
+		t.Fatal("expected the empty tenant view to stay scoped")
+	}
+
+	if err := empty.Log(NewLog().SetLevel(LEVEL_INFO).SetMessage("stamped").SetTenantID("acme")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	logs, err := empty.LogList(ctx, LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	if len(logs) != 2 {
+		t.Fatalf("expected the 2 logs without a tenant, got %d", len(logs))
+	}
+
+	for _, logEntry := range logs {
+		if logEntry.GetTenantID() != "" {
+			t.Fatalf("expected only logs without a tenant, got %q", logEntry.GetTenantID())
+		}
+	}
+
+	all, err := s.LogList(ctx, LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogList: %v", err)
+	}
+
+	ids := []string{}
+	for _, logEntry := range all {
+		ids = append(ids, logEntry.GetID())
+	}
+
+	if err := empty.LogDeleteByIDs(ctx, ids); err != nil {
+		t.Fatalf("unexpected error from LogDeleteByIDs: %v", err)
+	}
+
+	count, err := s.LogCount(ctx, LogQuery())
+	if err != nil {
+		t.Fatalf("unexpected error from LogCount: %v", err)
+	}
+
+	if count != 2 {
+		t.Fatalf("expected the logs of the tenants to be kept, got %d", count)
+	}
+
+	if err := empty.MigrateDown(ctx); err == nil {
+		t.Fatal("expected an error dropping the shared table from the empty tenant view")
+	}
+}
+
+func Test_DefaultTenantTableName(t *testing.T) {
+	if name := DefaultTenantTableName("log", "Acme-Corp"); name != "log_acme_corp_83f386d6300f" {
+		t.Fatalf("unexpected tenant table name %q", name)
+	}
+
+	names := map[string]string{}
+	for _, tenantID := range []string{"Acme", "acme", "acme-corp", "acme.corp", "acme_corp", ""} {
+		name := DefaultTenantTableName("log", tenantID)
+		if other, exists := names[name]; exists {
+			t.Fatalf("expected tenants %q and %q to have tables of their own, both got %q", tenantID, other, name)
+		}
+		names[name] = tenantID
+	}
+
+	long := DefaultTenantTableName("log", strings.Repeat("a", 100))
+	if len(long) != len("log_")+32+len("_")+12 {
+		t.Fatalf("expected the tenant ID to be cut, got %q", long)
+	}
+}
@@ -0,0 +1,47 @@
+package logstore
+
+import (
+	"embed"
+	"errors"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed ui
+var uiFiles embed.FS
+
+// NewUIHandler returns a handler serving a log viewer, for reading the logs
+// of the store from a browser, with the JSON API of NewHTTPHandler under
+// /api. The viewer has no external assets, so it also works offline.
+//
+// The options apply to the viewer and the API. Mount it under a prefix with
+// http.StripPrefix, keeping the trailing slash, i.e.
+//
+//	mux.Handle("/logs/", http.StripPrefix("/logs", logstore.NewUIHandler(logStore)))
+func NewUIHandler(store StoreInterface, opts ...HTTPHandlerOptions) http.Handler {
+	options := HTTPHandlerOptions{}
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	assets, err := fs.Sub(uiFiles, "ui")
+	if err != nil {
+		// the directory is embedded, so it is always there
+		panic(err)
+	}
+
+	files := http.FileServerFS(assets)
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/", http.StripPrefix("/api", NewHTTPHandler(store, options)))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if options.Authorize != nil && !options.Authorize(r) {
+			writeHTTPError(w, http.StatusForbidden, errors.New("forbidden"))
+			return
+		}
+
+		files.ServeHTTP(w, r)
+	})
+
+	return mux
+}
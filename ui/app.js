@@ -0,0 +1,287 @@
+(function () {
+  "use strict";
+
+  var PAGE_SIZE = 100;
+  var FOLLOW_INTERVAL = 2000;
+
+  var form = document.getElementById("filters");
+  var tbody = document.getElementById("logs");
+  var status = document.getElementById("status");
+  var more = document.getElementById("more");
+  var follow = document.getElementById("follow");
+
+  var offset = 0;
+  var loading = false;
+  var exhausted = false;
+  var newest = "";
+  var shown = {};
+  var followTimer = null;
+
+  // toStoredTime converts a datetime-local value, or a Date, to the UTC
+  // "YYYY-MM-DD HH:MM:SS" format the logs are stored in
+  function toStoredTime(value) {
+    var date = value instanceof Date ? value : new Date(value);
+    if (isNaN(date.getTime())) {
+      return "";
+    }
+    return date.toISOString().slice(0, 19).replace("T", " ");
+  }
+
+  function filterParams() {
+    var params = new URLSearchParams();
+    var data = new FormData(form);
+
+    data.forEach(function (value, name) {
+      if (value === "") {
+        return;
+      }
+      if (name === "time_gte" || name === "time_lte") {
+        value = toStoredTime(value);
+      }
+      params.set(name, value);
+    });
+
+    return params;
+  }
+
+  function setStatus(text, isError) {
+    status.textContent = text;
+    status.className = isError ? "status error" : "status";
+  }
+
+  function fetchJSON(url) {
+    return fetch(url, { headers: { Accept: "application/json" } }).then(function (response) {
+      return response.json().then(function (body) {
+        if (!response.ok) {
+          throw new Error(body.error || response.statusText);
+        }
+        return body;
+      });
+    });
+  }
+
+  function element(tag, className, text) {
+    var el = document.createElement(tag);
+    if (className) {
+      el.className = className;
+    }
+    if (text !== undefined) {
+      el.textContent = text;
+    }
+    return el;
+  }
+
+  function formatTime(value) {
+    return value ? value.replace("T", " ").replace("Z", "") : "";
+  }
+
+  function prettyContext(context) {
+    try {
+      return JSON.stringify(JSON.parse(context), null, 2);
+    } catch (e) {
+      return context;
+    }
+  }
+
+  function renderDetails(log) {
+    var row = element("tr", "details");
+    var cell = element("td");
+    cell.colSpan = 3;
+
+    var fields = element("dl");
+    [
+      ["ID", log.id],
+      ["Occurrences", log.occurrences > 1 ? String(log.occurrences) : ""],
+      ["Last seen", formatTime(log.last_seen)],
+      ["Fingerprint", log.fingerprint],
+      ["Trace ID", log.trace_id],
+      ["Span ID", log.span_id],
+      ["Request ID", log.request_id],
+      ["Service", [log.service_name, log.service_version].filter(Boolean).join(" ")],
+      ["Environment", log.environment],
+      ["Host", log.host_name],
+      ["Instance", log.instance_id],
+      ["Tenant", log.tenant_id]
+    ].forEach(function (field) {
+      if (!field[1]) {
+        return;
+      }
+      fields.appendChild(element("dt", "", field[0]));
+      fields.appendChild(element("dd", "", field[1]));
+    });
+    cell.appendChild(fields);
+
+    if (log.context) {
+      cell.appendChild(element("pre", "", prettyContext(log.context)));
+    }
+
+    if (log.stack) {
+      cell.appendChild(element("pre", "", log.stack));
+    }
+
+    var link = element("a", "", "Permalink");
+    link.href = "#id=" + encodeURIComponent(log.id);
+    cell.appendChild(link);
+
+    row.appendChild(cell);
+    return row;
+  }
+
+  function toggle(row, log) {
+    var next = row.nextElementSibling;
+    if (next && next.classList.contains("details")) {
+      next.remove();
+      row.classList.remove("selected");
+      return;
+    }
+    row.classList.add("selected");
+    row.after(renderDetails(log));
+  }
+
+  function renderLog(log) {
+    var row = element("tr", "entry");
+    row.dataset.id = log.id;
+
+    row.appendChild(element("td", "time", formatTime(log.time)));
+
+    var level = element("td");
+    level.appendChild(element("span", "level-badge level-" + log.level, log.level));
+    row.appendChild(level);
+
+    row.appendChild(element("td", "", log.message));
+
+    row.addEventListener("click", function () {
+      toggle(row, log);
+    });
+
+    return row;
+  }
+
+  function track(log) {
+    shown[log.id] = true;
+    var time = toStoredTime(new Date(log.time));
+    if (time > newest) {
+      newest = time;
+    }
+  }
+
+  function loadMore() {
+    if (loading || exhausted) {
+      return;
+    }
+
+    loading = true;
+    setStatus("Loading...");
+
+    var params = filterParams();
+    params.set("limit", PAGE_SIZE);
+    params.set("offset", offset);
+
+    fetchJSON("api/logs?" + params.toString())
+      .then(function (body) {
+        body.logs.forEach(function (log) {
+          if (shown[log.id]) {
+            return;
+          }
+          track(log);
+          tbody.appendChild(renderLog(log));
+        });
+
+        offset += body.logs.length;
+        exhausted = body.logs.length < PAGE_SIZE;
+        setStatus(exhausted ? (offset === 0 ? "No logs" : "") : "");
+      })
+      .catch(function (err) {
+        setStatus(err.message, true);
+      })
+      .finally(function () {
+        loading = false;
+      });
+  }
+
+  function poll() {
+    var params = filterParams();
+    params.set("order_by", "time");
+    params.set("order_direction", "asc");
+    params.set("limit", 1000);
+
+    if (newest && (!params.get("time_gte") || params.get("time_gte") < newest)) {
+      params.set("time_gte", newest);
+    }
+
+    fetchJSON("api/logs?" + params.toString())
+      .then(function (body) {
+        body.logs.forEach(function (log) {
+          if (shown[log.id]) {
+            return;
+          }
+          track(log);
+          offset++;
+          tbody.prepend(renderLog(log));
+        });
+      })
+      .catch(function (err) {
+        setStatus(err.message, true);
+      });
+  }
+
+  function setFollowing(on) {
+    clearInterval(followTimer);
+    followTimer = on ? setInterval(poll, FOLLOW_INTERVAL) : null;
+  }
+
+  function reset() {
+    tbody.textContent = "";
+    offset = 0;
+    exhausted = false;
+    newest = "";
+    shown = {};
+    loadMore();
+  }
+
+  function showPermalink() {
+    var match = location.hash.match(/^#id=(.+)$/);
+    if (!match) {
+      return;
+    }
+
+    var id = decodeURIComponent(match[1]);
+
+    fetchJSON("api/logs/" + encodeURIComponent(id))
+      .then(function (log) {
+        var row = tbody.querySelector('tr.entry[data-id="' + CSS.escape(id) + '"]');
+        if (!row) {
+          row = renderLog(log);
+          track(log);
+          tbody.prepend(row);
+        }
+        if (!row.classList.contains("selected")) {
+          toggle(row, log);
+        }
+        row.scrollIntoView({ block: "center" });
+      })
+      .catch(function (err) {
+        setStatus(err.message, true);
+      });
+  }
+
+  form.addEventListener("submit", function (event) {
+    event.preventDefault();
+    reset();
+  });
+
+  follow.addEventListener("change", function () {
+    setFollowing(follow.checked);
+  });
+
+  window.addEventListener("hashchange", showPermalink);
+
+  new IntersectionObserver(function (entries) {
+    if (entries[0].isIntersecting) {
+      loadMore();
+    }
+  }).observe(more);
+
+  reset();
+  showPermalink();
+})();
@@ -0,0 +1,75 @@
+package logstore
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_UIHandler(t *testing.T) {
+	s, err := NewStore(NewStoreOptions{
+		DB:                 InitDB(),
+		LogTableName:       "log_ui",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	if err := s.LogCreate(context.Background(), NewLog().SetLevel(LEVEL_INFO).SetMessage("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.StripPrefix("/viewer", NewUIHandler(s)))
+	defer server.Close()
+
+	get := func(path string) (int, string, string) {
+		t.Helper()
+		response, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer response.Body.Close()
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return response.StatusCode, response.Header.Get("Content-Type"), string(body)
+	}
+
+	code, _, body := get("/viewer/")
+	if code != http.StatusOK || !strings.Contains(body, `<script src="app.js">`) {
+		t.Fatalf("expected the viewer page, got %d %q", code, body)
+	}
+
+	for _, asset := range []string{"/viewer/app.js", "/viewer/style.css"} {
+		if code, _, _ := get(asset); code != http.StatusOK {
+			t.Fatalf("expected %s to be served, got %d", asset, code)
+		}
+	}
+
+	if strings.Contains(body, "http://") || strings.Contains(body, "https://") {
+		t.Fatal("expected no external assets")
+	}
+
+	code, contentType, body := get("/viewer/api/logs")
+	if code != http.StatusOK || contentType != "application/json" {
+		t.Fatalf("expected the JSON API, got %d %s", code, contentType)
+	}
+
+	list := HTTPLogList{}
+	if err := json.Unmarshal([]byte(body), &list); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(list.Logs) != 1 || list.Logs[0].Message != "hello" {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+}
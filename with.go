@@ -0,0 +1,91 @@
+package logstore
+
+import (
+	"encoding/json"
+	"maps"
+)
+
+// contextKeyComponent is the context key holding the name given with Named
+const contextKeyComponent = "component"
+
+// With returns a child logger which adds the fields to the context of
+// every entry it writes. Fields passed on each call override bound ones.
+// The child shares the database, table and buffered entries of its parent.
+func (st *storeImplementation) With(fields map[string]any) StoreInterface {
+	child := *st
+	child.boundFields = make(map[string]any, len(st.boundFields)+len(fields))
+	maps.Copy(child.boundFields, st.boundFields)
+	maps.Copy(child.boundFields, fields)
+	return &child
+}
+
+// Named returns a child logger which adds the component name to the
+// context of every entry it writes. Names of nested children are joined
+// with dots, i.e. "api.users".
+func (st *storeImplementation) Named(component string) StoreInterface {
+	child := *st
+	if st.name != "" {
+		child.name = st.name + "." + component
+	} else {
+		child.name = component
+	}
+	return &child
+}
+
+// applyBoundFields merges the bound fields and component name into the
+// context of the entry, keeping any key the entry already has. Contexts
+// which are not a JSON object are kept under the "context" key.
+func (st *storeImplementation) applyBoundFields(logEntry LogInterface) {
+	if len(st.boundFields) == 0 && st.name == "" {
+		return
+	}
+
+	object := map[string]json.RawMessage{}
+	context := logEntry.GetContext()
+
+	if context != "" {
+		if err := json.Unmarshal([]byte(context), &object); err != nil {
+			object = map[string]json.RawMessage{}
+			if json.Valid([]byte(context)) {
+				object["context"] = json.RawMessage(context)
+			} else {
+				encoded, _ := json.Marshal(context)
+				object["context"] = encoded
+			}
+		}
+	}
+
+	// a "null" context decodes to a nil map
+	if object == nil {
+		object = map[string]json.RawMessage{}
+	}
+
+	bound := maps.Clone(st.boundFields)
+	if bound == nil {
+		bound = map[string]any{}
+	}
+	if st.name != "" {
+		bound[contextKeyComponent] = st.name
+	}
+
+	for key, value := range bound {
+		if _, exists := object[key]; exists {
+			continue
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			st.logger.Error("JSON encode error", "error", err, "key", key)
+			continue
+		}
+		object[key] = encoded
+	}
+
+	merged, err := json.Marshal(object)
+	if err != nil {
+		st.logger.Error("JSON encode error", "error", err)
+		return
+	}
+
+	logEntry.SetContext(string(merged))
+}
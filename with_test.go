@@ -0,0 +1,126 @@
+package logstore
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func Test_Store_With(t *testing.T) {
+	db := InitDB()
+
+	s, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		LogTableName:       "log_with",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	requestLogger := s.With(map[string]any{"request_id": "r-1", "user": "bound"})
+
+	if err := requestLogger.InfoWithContext("call", map[string]any{"user": "per-call"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := requestLogger.Named("api").Named("users").Warn("named"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := requestLogger.DebugWithContext("string context", "plain"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := s.Info("parent"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+
+	contextOf := func(level string) map[string]any {
+		logs, err := s.LogList(ctx, LogQuery().SetLevel(level))
+		if err != nil {
+			t.Fatalf("unexpected error from LogList: %v", err)
+		}
+		if len(logs) != 1 {
+			t.Fatalf("expected 1 %s log, got %d", level, len(logs))
+		}
+		if logs[0].GetContext() == "" {
+			return nil
+		}
+		object := map[string]any{}
+		if err := json.Unmarshal([]byte(logs[0].GetContext()), &object); err != nil {
+			t.Fatalf("expected JSON object context, got %q", logs[0].GetContext())
+		}
+		return object
+	}
+
+	warnContext := contextOf(LEVEL_WARNING)
+	if warnContext["component"] != "api.users" || warnContext["request_id"] != "r-1" {
+		t.Fatalf("unexpected named context: %v", warnContext)
+	}
+
+	debugContext := contextOf(LEVEL_DEBUG)
+	if debugContext["context"] != "plain" || debugContext["request_id"] != "r-1" {
+		t.Fatalf("unexpected string context: %v", debugContext)
+	}
+
+	logs, err := s.LogList(ctx, LogQuery().SetLevel(LEVEL_INFO).SetMessageContains("call"))
+	if err != nil || len(logs) != 1 {
+		t.Fatalf("expected 1 call log, got %d (%v)", len(logs), err)
+	}
+
+	callContext := map[string]any{}
+	json.Unmarshal([]byte(logs[0].GetContext()), &callContext)
+	if callContext["user"] != "per-call" || callContext["request_id"] != "r-1" {
+		t.Fatalf("expected per-call fields to override bound ones, got %v", callContext)
+	}
+
+	logs, err = s.LogList(ctx, LogQuery().SetMessageContains("parent"))
+	if err != nil || len(logs) != 1 {
+		t.Fatalf("expected 1 parent log, got %d (%v)", len(logs), err)
+	}
+
+	if logs[0].GetContext() != "" {
+		t.Fatalf("expected the parent to have no bound fields, got %q", logs[0].GetContext())
+	}
+}
+
+func Test_Store_With_NilContext(t *testing.T) {
+	s, err := NewStore(NewStoreOptions{
+		DB:                 InitDB(),
+		LogTableName:       "log_with_nil_context",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	if err := s.With(map[string]any{"a": 1}).InfoWithContext("bound", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := s.Named("api").WarnWithContext("named", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	logs, err := s.LogList(context.Background(), LogQuery().SetOrderBy(COLUMN_MESSAGE).SetOrderDirection("asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 logs, got %d", len(logs))
+	}
+
+	if logs[0].GetContext() != `{"a":1}` {
+		t.Fatalf("unexpected context %s", logs[0].GetContext())
+	}
+
+	if logs[1].GetContext() != `{"component":"api"}` {
+		t.Fatalf("unexpected context %s", logs[1].GetContext())
+	}
+}
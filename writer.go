@@ -0,0 +1,142 @@
+package logstore
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// maxWriterLineLength is the length at which a line without a line break is
+// written anyway
+const maxWriterLineLength = 64 * 1024
+
+// WriterOptions define the options of NewWriter
+type WriterOptions struct {
+	// ParseLevel takes the level of each line from a prefix such as
+	// "[ERROR]", "[warn]", "WARN:" or "error:", which is removed from the
+	// message. Lines without a prefix keep the level of the writer.
+	ParseLevel bool
+}
+
+// writer writes each line written to it as an entry
+type writer struct {
+	store   StoreInterface
+	level   string
+	options WriterOptions
+	mutex   sync.Mutex
+	buffer  []byte
+}
+
+// NewWriter returns a writer splitting the bytes written to it into lines,
+// and adding each non-empty line as an entry of the level. An incomplete
+// line is kept until the rest of it is written.
+func NewWriter(store StoreInterface, level string, opts ...WriterOptions) io.Writer {
+	w := &writer{store: store, level: level}
+
+	if len(opts) > 0 {
+		w.options = opts[0]
+	}
+
+	if w.level == "" {
+		w.level = LEVEL_INFO
+	}
+
+	return w
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.buffer = append(w.buffer, p...)
+
+	for {
+		i := bytes.IndexByte(w.buffer, '\n')
+		if i < 0 && len(w.buffer) < maxWriterLineLength {
+			break
+		}
+
+		if i < 0 {
+			i = len(w.buffer)
+		}
+
+		line := string(w.buffer[:i])
+		w.buffer = w.buffer[min(i+1, len(w.buffer)):]
+
+		if err := w.writeLine(line); err != nil {
+			return len(p), err
+		}
+	}
+
+	if len(w.buffer) == 0 {
+		w.buffer = nil
+	}
+
+	return len(p), nil
+}
+
+// writeLine adds the line as an entry, unless it is blank
+func (w *writer) writeLine(line string) error {
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" {
+		return nil
+	}
+
+	level := w.level
+	if w.options.ParseLevel {
+		if prefixLevel, message, ok := parseLevelPrefix(line); ok {
+			level = prefixLevel
+			line = message
+		}
+	}
+
+	return w.store.Log(NewLog().SetLevel(level).SetMessage(line))
+}
+
+// parseLevelPrefix splits a "[LEVEL] message" or "LEVEL: message" line
+func parseLevelPrefix(line string) (string, string, bool) {
+	trimmed := strings.TrimLeftFunc(line, unicode.IsSpace)
+
+	var name, message string
+
+	if rest, ok := strings.CutPrefix(trimmed, "["); ok {
+		before, after, found := strings.Cut(rest, "]")
+		if !found {
+			return "", line, false
+		}
+		name, message = before, after
+	} else {
+		before, after, found := strings.Cut(trimmed, ":")
+		if !found || strings.ContainsFunc(before, func(r rune) bool { return !unicode.IsLetter(r) }) {
+			return "", line, false
+		}
+		name, message = before, after
+	}
+
+	level, err := parseImportLevel(strings.TrimSpace(name))
+	if err != nil {
+		return "", line, false
+	}
+
+	return level, strings.TrimLeftFunc(message, unicode.IsSpace), true
+}
+
+// RedirectStdLog sends the output of the standard log package to the store,
+// as info entries or the level of their prefix, without the date and time
+// added by the log package. The returned function restores the previous
+// output and flags.
+func RedirectStdLog(store StoreInterface) func() {
+	output := log.Writer()
+	flags := log.Flags()
+
+	log.SetOutput(NewWriter(store, LEVEL_INFO, WriterOptions{ParseLevel: true}))
+	log.SetFlags(0)
+
+	return func() {
+		log.SetOutput(output)
+		log.SetFlags(flags)
+	}
+}
@@ -0,0 +1,109 @@
+package logstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"testing"
+)
+
+func Test_Writer(t *testing.T) {
+	s, err := NewStore(NewStoreOptions{
+		DB:                 InitDB(),
+		LogTableName:       "log_writer",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	w := NewWriter(s, LEVEL_WARNING, WriterOptions{ParseLevel: true})
+
+	writes := []string{
+		"[ERROR] disk full\n",
+		"WARN: slow query\r\n\n",
+		"info: cache ",
+		"warmed\nhttp: TLS handshake error\n",
+		"[unknown] kept as is\n",
+		"no newline yet",
+	}
+
+	for _, write := range writes {
+		if _, err := fmt.Fprint(w, write); err != nil {
+			t.Fatalf("unexpected error from Write: %v", err)
+		}
+	}
+
+	logs, err := s.LogList(context.Background(), LogQuery().SetOrderBy(COLUMN_MESSAGE).SetOrderDirection("asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"disk full":                 LEVEL_ERROR,
+		"slow query":                LEVEL_WARNING,
+		"cache warmed":              LEVEL_INFO,
+		"http: TLS handshake error": LEVEL_WARNING,
+		"[unknown] kept as is":      LEVEL_WARNING,
+	}
+
+	if len(logs) != len(expected) {
+		t.Fatalf("expected %d logs, got %d", len(expected), len(logs))
+	}
+
+	for _, logEntry := range logs {
+		level, ok := expected[logEntry.GetMessage()]
+		if !ok || level != logEntry.GetLevel() {
+			t.Fatalf("unexpected log %q at level %s", logEntry.GetMessage(), logEntry.GetLevel())
+		}
+	}
+}
+
+func Test_RedirectStdLog(t *testing.T) {
+	s, err := NewStore(NewStoreOptions{
+		DB:                 InitDB(),
+		LogTableName:       "log_redirect",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatal("Store could not be created: " + err.Error())
+	}
+
+	output := log.Writer()
+	defer log.SetOutput(output)
+	log.SetOutput(io.Discard)
+
+	flags := log.Flags()
+	restore := RedirectStdLog(s)
+
+	log.Printf("[ERROR] payment %s failed", "p-1")
+	log.Println("started")
+
+	restore()
+
+	if log.Flags() != flags || log.Writer() != io.Discard {
+		t.Fatal("expected the flags and output to be restored")
+	}
+
+	log.Println("not stored")
+
+	logs, err := s.LogList(context.Background(), LogQuery().SetOrderBy(COLUMN_LEVEL).SetOrderDirection("asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 logs, got %d", len(logs))
+	}
+
+	if logs[0].GetLevel() != LEVEL_ERROR || logs[0].GetMessage() != "payment p-1 failed" {
+		t.Fatalf("unexpected log %s %q", logs[0].GetLevel(), logs[0].GetMessage())
+	}
+
+	if logs[1].GetLevel() != LEVEL_INFO || logs[1].GetMessage() != "started" {
+		t.Fatalf("unexpected log %s %q", logs[1].GetLevel(), logs[1].GetMessage())
+	}
+}
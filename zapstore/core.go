@@ -0,0 +1,145 @@
+// Package zapstore provides a zapcore.Core writing zap entries to a log
+// store, so services using zap can adopt logstore without changing their
+// logging calls.
+package zapstore
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/dracory/logstore"
+	"go.uber.org/zap/zapcore"
+)
+
+// Options define the options of NewCore
+type Options struct {
+	// LevelEnabler decides which levels are written, by default debug
+	// and above
+	LevelEnabler zapcore.LevelEnabler
+}
+
+// core implements zapcore.Core on top of a log store
+type core struct {
+	store   logstore.StoreInterface
+	enabler zapcore.LevelEnabler
+	fields  []zapcore.Field
+}
+
+var _ zapcore.Core = (*core)(nil)
+
+// NewCore returns a core adding each zap entry to the store, with the fields
+// encoded as the context JSON. Objects, arrays and namespaces are nested,
+// and the logger name, caller and stack are kept too.
+//
+// Fatal and panic entries are only logged: zap itself exits or panics after
+// writing them. Entries above the error level flush the store, so they are
+// not lost in its deduplication.
+func NewCore(store logstore.StoreInterface, opts ...Options) zapcore.Core {
+	c := &core{store: store, enabler: zapcore.DebugLevel}
+
+	if len(opts) > 0 && opts[0].LevelEnabler != nil {
+		c.enabler = opts[0].LevelEnabler
+	}
+
+	return c
+}
+
+func (c *core) Enabled(level zapcore.Level) bool {
+	return c.enabler.Enabled(level)
+}
+
+// Level reports the minimum enabled level, for zapcore.LevelOf
+func (c *core) Level() zapcore.Level {
+	return zapcore.LevelOf(c.enabler)
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	child := *c
+	child.fields = make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	child.fields = append(child.fields, c.fields...)
+	child.fields = append(child.fields, fields...)
+	return &child
+}
+
+func (c *core) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	encoder := zapcore.NewMapObjectEncoder()
+
+	if entry.LoggerName != "" {
+		encoder.AddString("logger", entry.LoggerName)
+	}
+
+	if entry.Caller.Defined {
+		encoder.AddString("caller", entry.Caller.TrimmedPath())
+	}
+
+	// fields added with With come first, so their namespaces also hold
+	// the fields of the entry
+	for _, field := range c.fields {
+		field.AddTo(encoder)
+	}
+
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+
+	logEntry := logstore.NewLog().
+		SetLevel(levelOf(entry.Level)).
+		SetMessage(entry.Message).
+		SetTime(entry.Time.UTC())
+
+	if len(encoder.Fields) > 0 {
+		contextBytes, err := json.Marshal(encoder.Fields)
+		if err != nil {
+			return err
+		}
+		logEntry.SetContext(string(contextBytes))
+	}
+
+	if entry.Stack != "" {
+		logEntry.SetStack(entry.Stack)
+	}
+
+	if err := c.store.Log(logEntry); err != nil {
+		return err
+	}
+
+	// like the cores of zap, as the process may exit or panic next
+	if entry.Level > zapcore.ErrorLevel {
+		return c.Sync()
+	}
+
+	return nil
+}
+
+// Sync flushes the entries held back by the deduplication of the store
+func (c *core) Sync() error {
+	return c.store.Flush(context.Background())
+}
+
+// levelOf maps a zap level to a log level. DPanic does not panic in
+// production, so it is stored as an error.
+func levelOf(level zapcore.Level) string {
+	switch {
+	case level < zapcore.DebugLevel:
+		return logstore.LEVEL_TRACE
+	case level == zapcore.DebugLevel:
+		return logstore.LEVEL_DEBUG
+	case level == zapcore.InfoLevel:
+		return logstore.LEVEL_INFO
+	case level == zapcore.WarnLevel:
+		return logstore.LEVEL_WARNING
+	case level == zapcore.ErrorLevel, level == zapcore.DPanicLevel:
+		return logstore.LEVEL_ERROR
+	case level == zapcore.PanicLevel:
+		return logstore.LEVEL_PANIC
+	}
+
+	return logstore.LEVEL_FATAL
+}
@@ -0,0 +1,158 @@
+package zapstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dracory/logstore"
+	"github.com/dracory/logstore/internal/storetest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// user is a zap.Object for the tests
+type user struct {
+	id    string
+	roles []string
+}
+
+func (u user) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	encoder.AddString("id", u.id)
+	return encoder.AddArray("roles", zapcore.ArrayMarshalerFunc(func(array zapcore.ArrayEncoder) error {
+		for _, role := range u.roles {
+			array.AppendString(role)
+		}
+		return nil
+	}))
+}
+
+func Test_Core(t *testing.T) {
+	store := storetest.NewStore(t, logstore.NewStoreOptions{LogTableName: "log_zap"})
+
+	logger := zap.New(NewCore(store, Options{LevelEnabler: zapcore.InfoLevel})).Named("billing")
+
+	logger.Debug("filtered out")
+
+	logger.
+		With(zap.String("request_id", "r-1"), zap.Namespace("payment")).
+		Error("payment failed",
+			zap.Int("amount", 42),
+			zap.Object("user", user{id: "u-1", roles: []string{"admin", "billing"}}),
+			zap.Strings("tags", []string{"card"}),
+			zap.Error(errors.New("card declined")))
+
+	logger.Warn("slow query")
+
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("unexpected error from Sync: %v", err)
+	}
+
+	logs, err := store.LogList(context.Background(), logstore.LogQuery().
+		SetOrderBy(logstore.COLUMN_LEVEL).
+		SetOrderDirection("asc"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 logs, got %d", len(logs))
+	}
+
+	if logs[0].GetLevel() != logstore.LEVEL_ERROR || logs[0].GetMessage() != "payment failed" {
+		t.Fatalf("unexpected log %s %q", logs[0].GetLevel(), logs[0].GetMessage())
+	}
+
+	if logs[1].GetLevel() != logstore.LEVEL_WARNING {
+		t.Fatalf("expected a warning, got %s", logs[1].GetLevel())
+	}
+
+	fields := map[string]any{}
+	if err := json.Unmarshal([]byte(logs[0].GetContext()), &fields); err != nil {
+		t.Fatalf("expected JSON context: %v", err)
+	}
+
+	if fields["logger"] != "billing" || fields["request_id"] != "r-1" {
+		t.Fatalf("unexpected context: %v", fields)
+	}
+
+	payment, ok := fields["payment"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected the fields in the payment namespace: %v", fields)
+	}
+
+	if payment["amount"] != float64(42) || payment["error"] != "card declined" {
+		t.Fatalf("unexpected payment fields: %v", payment)
+	}
+
+	userFields, ok := payment["user"].(map[string]any)
+	if !ok || userFields["id"] != "u-1" || len(userFields["roles"].([]any)) != 2 {
+		t.Fatalf("unexpected user object: %v", payment["user"])
+	}
+
+	if !strings.Contains(logs[0].GetContext(), `"tags":["card"]`) {
+		t.Fatalf("expected the tags array: %s", logs[0].GetContext())
+	}
+
+	if strings.Contains(logs[1].GetContext(), "request_id") {
+		t.Fatal("expected fields added with With to stay on their logger")
+	}
+}
+
+func Test_Core_SyncsAboveError(t *testing.T) {
+	store := storetest.NewStore(t, logstore.NewStoreOptions{
+		LogTableName:        "log_zap_dedup",
+		DeduplicationWindow: time.Hour,
+	})
+
+	logger := zap.New(NewCore(store))
+
+	logger.Error("held back")
+	logger.DPanic("dpanic")
+
+	func() {
+		defer func() { recover() }()
+		logger.Panic("panic")
+	}()
+
+	logs, err := store.LogList(context.Background(), logstore.LogQuery().
+		SetOrderBy(logstore.COLUMN_MESSAGE).
+		SetOrderDirection("asc"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := []string{}
+	for _, logEntry := range logs {
+		messages = append(messages, logEntry.GetMessage())
+	}
+
+	// the sync after the dpanic entry flushes the error entry too
+	if strings.Join(messages, ",") != "dpanic,held back,panic" {
+		t.Fatalf("expected the entries to be written without calling Sync, got %v", messages)
+	}
+}
+
+func Test_levelOf(t *testing.T) {
+	levels := map[zapcore.Level]string{
+		zapcore.DebugLevel - 1: logstore.LEVEL_TRACE,
+		zapcore.DebugLevel:     logstore.LEVEL_DEBUG,
+		zapcore.InfoLevel:      logstore.LEVEL_INFO,
+		zapcore.WarnLevel:      logstore.LEVEL_WARNING,
+		zapcore.ErrorLevel:     logstore.LEVEL_ERROR,
+		zapcore.DPanicLevel:    logstore.LEVEL_ERROR,
+		zapcore.PanicLevel:     logstore.LEVEL_PANIC,
+		zapcore.FatalLevel:     logstore.LEVEL_FATAL,
+	}
+
+	for level, expected := range levels {
+		if actual := levelOf(level); actual != expected {
+			t.Errorf("expected %s for %s, got %s", expected, level, actual)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Package zerologstore provides a zerolog writer adding entries to a log
+// store, so services using zerolog can adopt logstore without changing
+// their logging calls.
+package zerologstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dracory/logstore"
+	"github.com/rs/zerolog"
+)
+
+// LevelWriter adds the JSON events written by zerolog to a log store
+type LevelWriter struct {
+	store logstore.StoreInterface
+}
+
+var _ zerolog.LevelWriter = (*LevelWriter)(nil)
+
+// NewLevelWriter returns a writer parsing each zerolog JSON event into an
+// entry of the store. The time, level and message are taken from the fields
+// named by zerolog.TimestampFieldName, zerolog.LevelFieldName and
+// zerolog.MessageFieldName, and the other fields are kept as the context.
+//
+// Fatal and panic events are only logged: zerolog itself exits or panics
+// after writing them. They flush the store, so they are not lost in its
+// deduplication.
+func NewLevelWriter(store logstore.StoreInterface) *LevelWriter {
+	return &LevelWriter{store: store}
+}
+
+// Write adds the event, taking its level from the level field
+func (w *LevelWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel adds the event at the level, or the level of its level field
+// for events without one
+func (w *LevelWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	fields := map[string]any{}
+
+	decoder := json.NewDecoder(bytes.NewReader(p))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&fields); err != nil {
+		return 0, fmt.Errorf("zerologstore: invalid event: %w", err)
+	}
+
+	if levelName, ok := fields[zerolog.LevelFieldName].(string); ok {
+		if level == zerolog.NoLevel {
+			if parsed, err := zerolog.ParseLevel(levelName); err == nil {
+				level = parsed
+			}
+		}
+		delete(fields, zerolog.LevelFieldName)
+	}
+
+	if level == zerolog.Disabled {
+		return len(p), nil
+	}
+
+	logEntry := logstore.NewLog().SetLevel(levelOf(level))
+
+	if message, ok := fields[zerolog.MessageFieldName].(string); ok {
+		logEntry.SetMessage(message)
+		delete(fields, zerolog.MessageFieldName)
+	}
+
+	if value, ok := fields[zerolog.TimestampFieldName]; ok {
+		if t, ok := parseTime(value); ok {
+			logEntry.SetTime(t.UTC())
+			delete(fields, zerolog.TimestampFieldName)
+		}
+	}
+
+	if len(fields) > 0 {
+		contextBytes, err := json.Marshal(fields)
+		if err != nil {
+			return 0, err
+		}
+		logEntry.SetContext(string(contextBytes))
+	}
+
+	if err := w.store.Log(logEntry); err != nil {
+		return 0, err
+	}
+
+	// zerolog exits or panics next
+	if level == zerolog.FatalLevel || level == zerolog.PanicLevel {
+		if err := w.store.Flush(context.Background()); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// parseTime parses a time field written with zerolog.TimeFieldFormat
+func parseTime(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case string:
+		if t, err := time.Parse(zerolog.TimeFieldFormat, v); err == nil {
+			return t, true
+		}
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t, true
+		}
+	case json.Number:
+		number, err := v.Int64()
+		if err != nil {
+			return time.Time{}, false
+		}
+
+		switch zerolog.TimeFieldFormat {
+		case zerolog.TimeFormatUnixMs:
+			return time.UnixMilli(number), true
+		case zerolog.TimeFormatUnixMicro:
+			return time.UnixMicro(number), true
+		case zerolog.TimeFormatUnixNano:
+			return time.Unix(0, number), true
+		}
+
+		return time.Unix(number, 0), true
+	}
+
+	return time.Time{}, false
+}
+
+// levelOf maps a zerolog level to a log level. Events logged without a
+// level are stored as info.
+func levelOf(level zerolog.Level) string {
+	switch level {
+	case zerolog.TraceLevel:
+		return logstore.LEVEL_TRACE
+	case zerolog.DebugLevel:
+		return logstore.LEVEL_DEBUG
+	case zerolog.WarnLevel:
+		return logstore.LEVEL_WARNING
+	case zerolog.ErrorLevel:
+		return logstore.LEVEL_ERROR
+	case zerolog.FatalLevel:
+		return logstore.LEVEL_FATAL
+	case zerolog.PanicLevel:
+		return logstore.LEVEL_PANIC
+	}
+
+	return logstore.LEVEL_INFO
+}
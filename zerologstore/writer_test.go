@@ -0,0 +1,126 @@
+package zerologstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/dracory/logstore"
+	"github.com/dracory/logstore/internal/storetest"
+	"github.com/rs/zerolog"
+)
+
+func Test_LevelWriter(t *testing.T) {
+	store := storetest.NewStore(t, logstore.NewStoreOptions{LogTableName: "log_zerolog"})
+
+	at := time.Date(2026, time.October, 19, 14, 0, 0, 0, time.UTC)
+	zerolog.TimestampFunc = func() time.Time { return at }
+	defer func() { zerolog.TimestampFunc = time.Now }()
+
+	logger := zerolog.New(zerolog.MultiLevelWriter(io.Discard, NewLevelWriter(store))).
+		With().Timestamp().Str("service", "billing").Logger()
+
+	logger.Error().
+		Err(errors.New("card declined")).
+		Dict("user", zerolog.Dict().Str("id", "u-1")).
+		Int("attempt", 2).
+		Msg("payment failed")
+
+	logger.Warn().Msg("slow query")
+	logger.Log().Msg("without level")
+
+	logs, err := store.LogList(context.Background(), logstore.LogQuery().
+		SetOrderBy(logstore.COLUMN_LEVEL).
+		SetOrderDirection("asc"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(logs) != 3 {
+		t.Fatalf("expected 3 logs, got %d", len(logs))
+	}
+
+	logEntry := logs[0]
+
+	if logEntry.GetLevel() != logstore.LEVEL_ERROR || logEntry.GetMessage() != "payment failed" {
+		t.Fatalf("unexpected log %s %q", logEntry.GetLevel(), logEntry.GetMessage())
+	}
+
+	if !logEntry.GetTime().Equal(at) {
+		t.Fatalf("expected the event time, got %v", logEntry.GetTime())
+	}
+
+	fields := map[string]any{}
+	if err := json.Unmarshal([]byte(logEntry.GetContext()), &fields); err != nil {
+		t.Fatalf("expected JSON context: %v", err)
+	}
+
+	user, _ := fields["user"].(map[string]any)
+	if fields["service"] != "billing" || fields["error"] != "card declined" || fields["attempt"] != float64(2) || user["id"] != "u-1" {
+		t.Fatalf("unexpected context: %v", fields)
+	}
+
+	if _, ok := fields["level"]; ok {
+		t.Fatalf("expected the level to be left out of the context: %v", fields)
+	}
+
+	if logs[1].GetLevel() != logstore.LEVEL_INFO || logs[1].GetMessage() != "without level" {
+		t.Fatalf("unexpected log %s %q", logs[1].GetLevel(), logs[1].GetMessage())
+	}
+
+	if logs[2].GetLevel() != logstore.LEVEL_WARNING {
+		t.Fatalf("expected a warning, got %s", logs[2].GetLevel())
+	}
+}
+
+func Test_LevelWriter_Write(t *testing.T) {
+	store := storetest.NewStore(t, logstore.NewStoreOptions{LogTableName: "log_zerolog"})
+	w := NewLevelWriter(store)
+
+	if _, err := w.Write([]byte(`{"level":"debug","time":1792418400,"message":"plain write"}` + "\n")); err != nil {
+		t.Fatalf("unexpected error from Write: %v", err)
+	}
+
+	if _, err := w.Write([]byte("not json\n")); err == nil {
+		t.Fatal("expected an error for an invalid event")
+	}
+
+	logs, err := store.LogList(context.Background(), logstore.LogQuery())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(logs) != 1 || logs[0].GetLevel() != logstore.LEVEL_DEBUG || logs[0].GetTime().Unix() != 1792418400 {
+		t.Fatalf("unexpected logs: %v", logs)
+	}
+}
+
+func Test_LevelWriter_FlushesFatalAndPanic(t *testing.T) {
+	store := storetest.NewStore(t, logstore.NewStoreOptions{
+		LogTableName:        "log_zerolog_dedup",
+		DeduplicationWindow: time.Hour,
+	})
+
+	logger := zerolog.New(NewLevelWriter(store))
+
+	// WithLevel logs at the fatal level without exiting
+	logger.WithLevel(zerolog.FatalLevel).Msg("fatal")
+
+	func() {
+		defer func() { recover() }()
+		logger.Panic().Msg("panic")
+	}()
+
+	count, err := store.LogCount(context.Background(), logstore.LogQuery())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 2 {
+		t.Fatalf("expected the fatal and panic entries to be flushed, got %d", count)
+	}
+}